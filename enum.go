@@ -0,0 +1,170 @@
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"sort"
+)
+
+// getTypeEnums collects the constants of the named type declared in the package,
+// like StatusActive in `const StatusActive Status = "active"`, in the order of declaration.
+func (p *parser) getTypeEnums(pkgPath, typeName string) ([]interface{}, []string) {
+	enums := []interface{}{}
+	varNames := []string{}
+
+	astPkgs, err := p.getPkgAst(pkgPath)
+	if err != nil {
+		p.debugf("getTypeEnums: parse of %s package cause error: %s", pkgPath, err)
+		return nil, nil
+	}
+	for _, astPackage := range astPkgs {
+		fileNames := []string{}
+		for fileName := range astPackage.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			for _, astDeclaration := range astPackage.Files[fileName].Decls {
+				astGenDeclaration, ok := astDeclaration.(*ast.GenDecl)
+				if !ok || astGenDeclaration.Tok != token.CONST {
+					continue
+				}
+				// an empty spec repeats the type and values of the previous one, with the next iota
+				var specType ast.Expr
+				var specValues []ast.Expr
+				for iota, astSpec := range astGenDeclaration.Specs {
+					valueSpec, ok := astSpec.(*ast.ValueSpec)
+					if !ok {
+						continue
+					}
+					if valueSpec.Type != nil || len(valueSpec.Values) != 0 {
+						specType, specValues = valueSpec.Type, valueSpec.Values
+					}
+					for i, name := range valueSpec.Names {
+						if name.Name == "_" || i >= len(specValues) {
+							continue
+						}
+						if !isConstOfType(specType, specValues[i], typeName) {
+							continue
+						}
+						value, ok := evalConstExpr(specValues[i], iota)
+						if !ok {
+							p.debugf("getTypeEnums cannot evaluate %s of %s", name.Name, typeName)
+							continue
+						}
+						enums = append(enums, value)
+						varNames = append(varNames, name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return enums, varNames
+}
+
+// isConstOfType checks the constant is declared as `Name T = value` or `Name = T(value)`.
+func isConstOfType(specType, value ast.Expr, typeName string) bool {
+	if astIdent, ok := specType.(*ast.Ident); ok {
+		return astIdent.Name == typeName
+	}
+	if specType != nil {
+		return false
+	}
+	if astCallExpr, ok := value.(*ast.CallExpr); ok {
+		if astIdent, ok := astCallExpr.Fun.(*ast.Ident); ok {
+			return astIdent.Name == typeName && len(astCallExpr.Args) == 1
+		}
+	}
+	return false
+}
+
+// evalConstExpr evaluates a constant expression made of literals and iota.
+func evalConstExpr(expr ast.Expr, iota int) (interface{}, bool) {
+	value := evalConstValue(expr, iota)
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value), true
+	case constant.Bool:
+		return constant.BoolVal(value), true
+	case constant.Int:
+		if v, exact := constant.Int64Val(value); exact {
+			return v, true
+		}
+	case constant.Float:
+		v, _ := constant.Float64Val(value)
+		return v, true
+	}
+	return nil, false
+}
+
+func evalConstValue(expr ast.Expr, iota int) constant.Value {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true", "false":
+			return constant.MakeBool(e.Name == "true")
+		}
+	case *ast.ParenExpr:
+		return evalConstValue(e.X, iota)
+	case *ast.CallExpr:
+		// conversion, like Status("active")
+		if len(e.Args) == 1 {
+			return evalConstValue(e.Args[0], iota)
+		}
+	case *ast.UnaryExpr:
+		// the operations of invalid kinds, like ^1.5, are unknown instead of panics of constant
+		x := evalConstValue(e.X, iota)
+		switch {
+		case e.Op == token.ADD || e.Op == token.SUB:
+			if isNumericConstant(x) {
+				return constant.UnaryOp(e.Op, x, 0)
+			}
+		case e.Op == token.XOR && x.Kind() == constant.Int, e.Op == token.NOT && x.Kind() == constant.Bool:
+			return constant.UnaryOp(e.Op, x, 0)
+		}
+	case *ast.BinaryExpr:
+		x, y := evalConstValue(e.X, iota), evalConstValue(e.Y, iota)
+		switch e.Op {
+		case token.SHL, token.SHR:
+			if s, ok := constant.Uint64Val(y); ok && x.Kind() == constant.Int {
+				return constant.Shift(x, e.Op, uint(s))
+			}
+		case token.QUO, token.REM:
+			if !isNumericConstant(x) || !isNumericConstant(y) || constant.Sign(y) == 0 {
+				break
+			}
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				// integer division
+				if e.Op == token.QUO {
+					return constant.BinaryOp(x, token.QUO_ASSIGN, y)
+				}
+				return constant.BinaryOp(x, token.REM, y)
+			} else if e.Op == token.QUO {
+				return constant.BinaryOp(x, token.QUO, y)
+			}
+		case token.ADD:
+			if (x.Kind() == constant.String && y.Kind() == constant.String) || (isNumericConstant(x) && isNumericConstant(y)) {
+				return constant.BinaryOp(x, e.Op, y)
+			}
+		case token.SUB, token.MUL:
+			if isNumericConstant(x) && isNumericConstant(y) {
+				return constant.BinaryOp(x, e.Op, y)
+			}
+		case token.AND, token.OR, token.XOR, token.AND_NOT:
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				return constant.BinaryOp(x, e.Op, y)
+			}
+		}
+	}
+	return constant.MakeUnknown()
+}
+
+func isNumericConstant(value constant.Value) bool {
+	return value.Kind() == constant.Int || value.Kind() == constant.Float
+}
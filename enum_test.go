@@ -0,0 +1,97 @@
+package main
+
+import (
+	"go/ast"
+	goparser "go/parser"
+	"reflect"
+	"testing"
+)
+
+func TestEvalConstExpr(t *testing.T) {
+	tests := []struct {
+		expr string
+		iota int
+		want interface{}
+		ok   bool
+	}{
+		{`"active"`, 0, "active", true},
+		{"`raw`", 0, "raw", true},
+		{`"a" + "b"`, 0, "ab", true},
+		{`42`, 0, int64(42), true},
+		{`0x10`, 0, int64(16), true},
+		{`-1`, 0, int64(-1), true},
+		{`^0`, 0, int64(-1), true},
+		{`iota`, 3, int64(3), true},
+		{`iota + 1`, 3, int64(4), true},
+		{`(iota + 1) * 10`, 2, int64(30), true},
+		{`1 << iota`, 4, int64(16), true},
+		{`1024 >> iota`, 2, int64(256), true},
+		{`7 / 2`, 0, int64(3), true},
+		{`7 % 2`, 0, int64(1), true},
+		{`6 &^ 2`, 0, int64(4), true},
+		{`7.0 / 2`, 0, 3.5, true},
+		{`1.5`, 0, 1.5, true},
+		{`true`, 0, true, true},
+		{`!false`, 0, true, true},
+		{`Status("active")`, 0, "active", true},
+		{`Level(iota * 2)`, 2, int64(4), true},
+		{`1 << 70`, 0, nil, false},
+		{`1 / 0`, 0, nil, false},
+		{`1 % 0`, 0, nil, false},
+		{`"a" + 1`, 0, nil, false},
+		{`^1.5`, 0, nil, false},
+		{`!1`, 0, nil, false},
+		{`-"a"`, 0, nil, false},
+		{`1.5 % 1`, 0, nil, false},
+		{`1.5 << 1`, 0, nil, false},
+		{`1.5 & 1`, 0, nil, false},
+		{`1.0 / 0`, 0, nil, false},
+		{`true + true`, 0, nil, false},
+		{`"a" * 2`, 0, nil, false},
+		{`other`, 0, nil, false},
+		{`pkg.Const`, 0, nil, false},
+	}
+	for _, tt := range tests {
+		expr, err := goparser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := evalConstExpr(expr, tt.iota)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("evalConstExpr(%s, %d) = %v (%T), %v, want %v (%T), %v", tt.expr, tt.iota, got, got, ok, tt.want, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsConstOfType(t *testing.T) {
+	tests := []struct {
+		specType string
+		value    string
+		want     bool
+	}{
+		{"Status", `"active"`, true},
+		{"Other", `"active"`, false},
+		{"pkg.Status", `"active"`, false},
+		{"", `Status("active")`, true},
+		{"", `Other("active")`, false},
+		{"", `Status("a", "b")`, false},
+		{"", `"active"`, false},
+		{"", `iota`, false},
+	}
+	for _, tt := range tests {
+		var specType ast.Expr
+		if tt.specType != "" {
+			var err error
+			if specType, err = goparser.ParseExpr(tt.specType); err != nil {
+				t.Fatal(err)
+			}
+		}
+		value, err := goparser.ParseExpr(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := isConstOfType(specType, value, "Status"); got != tt.want {
+			t.Errorf("isConstOfType(%s, %s, Status) = %v, want %v", tt.specType, tt.value, got, tt.want)
+		}
+	}
+}
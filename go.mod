@@ -7,4 +7,5 @@ require (
 	github.com/mikunalpha/go-module v0.0.0-20190521120234-12aa2dc244ca
 	github.com/urfave/cli v1.20.0
 	github.com/uudashr/go-module v0.0.0-20180827225833-c0ca9c3a4966 // indirect
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -30,6 +30,26 @@ var flags = []cli.Flag{
 		Value: "oas.json",
 		Usage: "output file",
 	},
+	cli.StringFlag{
+		Name:  "format",
+		Value: "",
+		Usage: "output format, json or yaml (inferred from the output file extension when empty)",
+	},
+	cli.StringFlag{
+		Name:  "openapi-version",
+		Value: "3.0",
+		Usage: "version of the generated document, 3.0, 3.1 or 2.0 (Swagger)",
+	},
+	cli.StringFlag{
+		Name:  "type-mapping",
+		Value: "",
+		Usage: "YAML or JSON file mapping fully qualified Go types to schemas",
+	},
+	cli.StringFlag{
+		Name:  "marshaler-fallback",
+		Value: "",
+		Usage: "schema of the types implementing json.Marshaler without @Schema, in JSON or YAML, like '{type: string}'",
+	},
 	cli.BoolFlag{
 		Name:  "debug",
 		Usage: "show debug message",
@@ -37,12 +57,12 @@ var flags = []cli.Flag{
 }
 
 func action(c *cli.Context) error {
-	p, err := newParser(c.GlobalString("module-path"), c.GlobalString("main-file-path"), c.GlobalString("handler-path"), c.GlobalBool("debug"))
+	p, err := newParser(c.GlobalString("module-path"), c.GlobalString("main-file-path"), c.GlobalString("handler-path"), c.GlobalString("openapi-version"), c.GlobalString("type-mapping"), c.GlobalString("marshaler-fallback"), c.GlobalBool("debug"))
 	if err != nil {
 		return err
 	}
 	// fmt.Printf("%+v\n", p)
-	return p.CreateOASFile(c.GlobalString("output"))
+	return p.CreateOASFile(c.GlobalString("output"), c.GlobalString("format"))
 }
 
 func main() {
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// parseMarshalerSchemaObject sets the schema of a type whose JSON is not the one of its declaration,
+// and reports whether it is set. The schema is the @Schema comment of the type, in JSON or YAML,
+//
+//	// @Schema {type: string, format: date}
+//	type Date struct {
+//		time.Time
+//	}
+//
+// or else a string for a type implementing encoding.TextMarshaler. A type implementing json.Marshaler
+// without @Schema has the schema of --marshaler-fallback, which is any value by default.
+func (p *parser) parseMarshalerSchemaObject(pkgPath, pkgName string, schemaObject *SchemaObject, typeSpec *ast.TypeSpec) (bool, error) {
+	typeName := typeSpec.Name.String()
+	schema, err := getSchemaComment(typeSpec.Doc)
+	if err != nil {
+		return false, fmt.Errorf("parseMarshalerSchemaObject: @Schema of %s: %s", typeName, err)
+	}
+	if schema == nil {
+		methodNames := p.TypeMethods[pkgName][typeName]
+		if isInStringList(methodNames, "MarshalJSON") {
+			schema = &SchemaObject{}
+			if p.MarshalerFallback != nil {
+				p.warnf("%s.%s implements json.Marshaler without @Schema, its schema is the one of --marshaler-fallback", pkgName, typeName)
+				*schema = *p.MarshalerFallback
+			} else {
+				p.warnf("%s.%s implements json.Marshaler without @Schema, its schema is any value, set @Schema or --marshaler-fallback", pkgName, typeName)
+			}
+		} else if isInStringList(methodNames, "MarshalText") {
+			schema = &SchemaObject{Type: "string"}
+			// the constants are only the values of the text when they are strings
+			enums, varNames := p.getTypeEnums(pkgPath, typeName)
+			if len(enums) != 0 && isStringEnums(enums) {
+				schema.Enum = enums
+				schema.EnumVarNames = varNames
+			}
+		} else {
+			return false, nil
+		}
+	}
+
+	id, schemaPkgName := schemaObject.ID, schemaObject.PkgName
+	*schemaObject = *schema
+	schemaObject.ID, schemaObject.PkgName = id, schemaPkgName
+	return true, nil
+}
+
+// getSchemaComment returns the schema of the @Schema comment, or nil when there is no such comment.
+func getSchemaComment(astCommentGroup *ast.CommentGroup) (*SchemaObject, error) {
+	if astCommentGroup == nil {
+		return nil, nil
+	}
+	for _, astComment := range astCommentGroup.List {
+		comment := strings.TrimSpace(strings.TrimLeft(astComment.Text, "/"))
+		if len(comment) == 0 || strings.ToLower(strings.Fields(comment)[0]) != "@schema" {
+			continue
+		}
+		schema := &SchemaObject{}
+		err := unmarshalYaml([]byte(strings.TrimSpace(comment[len("@schema"):])), schema)
+		if err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+	return nil, nil
+}
+
+func isStringEnums(enums []interface{}) bool {
+	for _, enum := range enums {
+		if _, ok := enum.(string); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -1,13 +1,28 @@
 package main
 
-import "github.com/iancoleman/orderedmap"
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+)
 
 const (
-	OpenAPIVersion = "3.0.0"
+	OpenAPIVersion30 = "3.0.0"
+	OpenAPIVersion31 = "3.1.0"
+
+	JSONSchemaDialect202012 = "https://json-schema.org/draft/2020-12/schema"
 
 	ContentTypeText = "text/plain"
 	ContentTypeJson = "application/json"
 	ContentTypeForm = "multipart/form-data"
+
+	ContentTypeFormUrlencoded = "application/x-www-form-urlencoded"
+	ContentTypeOctetStream    = "application/octet-stream"
+
+	OutputFormatJson = "json"
+	OutputFormatYaml = "yaml"
 )
 
 type OpenAPIObject struct {
@@ -16,6 +31,10 @@ type OpenAPIObject struct {
 	Servers []ServerObject `json:"servers,omitempty"`
 	Paths   PathsObject    `json:"paths"` // Required
 
+	// Webhooks and JSONSchemaDialect are only available since OpenAPI 3.1
+	Webhooks          map[string]*PathItemObject `json:"webhooks,omitempty"`
+	JSONSchemaDialect string                     `json:"jsonSchemaDialect,omitempty"`
+
 	Components ComponentsOjbect      `json:"components,omitempty"` // Required for Authorization header
 	Security   []map[string][]string `json:"security,omitempty"`
 
@@ -70,20 +89,29 @@ type PathItemObject struct {
 }
 
 type OperationObject struct {
+	Accepts  []string `json:"-"` // For goas
+	Produces []string `json:"-"` // For goas
+
 	Responses ResponsesObject `json:"responses"` // Required
 
 	Tags        []string           `json:"tags,omitempty"`
 	Summary     string             `json:"summary,omitempty"`
 	Description string             `json:"description,omitempty"`
+	OperationID string             `json:"operationId,omitempty"`
 	Parameters  []ParameterObject  `json:"parameters,omitempty"`
 	RequestBody *RequestBodyObject `json:"requestBody,omitempty"`
 
+	Deprecated bool `json:"deprecated,omitempty"`
+
+	// Sunset is the date when the operation will be removed
+	Sunset string `json:"x-sunset,omitempty"`
+
+	// Security overrides the security of the document, use a pointer to output empty security
+	Security *[]map[string][]string `json:"security,omitempty"`
+
 	// Tags
 	// ExternalDocs
-	// OperationID
 	// Callbacks
-	// Deprecated
-	// Security
 	// Servers
 }
 
@@ -96,14 +124,15 @@ type ParameterObject struct {
 	Example     interface{}   `json:"example,omitempty"`
 	Schema      *SchemaObject `json:"schema,omitempty"`
 
+	Style         string `json:"style,omitempty"`
+	Explode       *bool  `json:"explode,omitempty"`
+	AllowReserved bool   `json:"allowReserved,omitempty"`
+
 	// Ref is used when ParameterOjbect is as a ReferenceObject
 	Ref string `json:"$ref,omitempty"`
 
 	// Deprecated
 	// AllowEmptyValue
-	// Style
-	// Explode
-	// AllowReserved
 	// Examples
 	// Content
 }
@@ -134,49 +163,79 @@ type SchemaObject struct {
 	ID                 string              `json:"-"` // For goas
 	PkgName            string              `json:"-"` // For goas
 	FieldName          string              `json:"-"` // For goas
+	FieldTag           string              `json:"-"` // For goas
 	DisabledFieldNames map[string]struct{} `json:"-"` // For goas
 
-	Type        string                 `json:"type,omitempty"`
-	Format      string                 `json:"format,omitempty"`
-	Required    []string               `json:"required,omitempty"`
-	Properties  *orderedmap.OrderedMap `json:"properties,omitempty"`
-	Description string                 `json:"description,omitempty"`
-	Items       *SchemaObject          `json:"items,omitempty"` // use ptr to prevent recursive error
-	Example     interface{}            `json:"example,omitempty"`
-	Deprecated  bool                   `json:"deprecated,omitempty"`
+	// Types replaces Type in the output when it is set (OpenAPI 3.1)
+	Types []string `json:"-"`
+
+	Type         string                 `json:"type,omitempty"`
+	Format       string                 `json:"format,omitempty"`
+	Required     []string               `json:"required,omitempty"`
+	Properties   *orderedmap.OrderedMap `json:"properties,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	Items        *SchemaObject          `json:"items,omitempty"` // use ptr to prevent recursive error
+	Example      interface{}            `json:"example,omitempty"`
+	Examples     []interface{}          `json:"examples,omitempty"` // OpenAPI 3.1
+	Enum         []interface{}          `json:"enum,omitempty"`
+	EnumVarNames []string               `json:"x-enum-varnames,omitempty"` // names of the Go constants of Enum
+	Const        interface{}            `json:"const,omitempty"`           // OpenAPI 3.1
+	Nullable     bool                   `json:"nullable,omitempty"`
+	Deprecated   bool                   `json:"deprecated,omitempty"`
+
+	Minimum          *float64    `json:"minimum,omitempty"`
+	Maximum          *float64    `json:"maximum,omitempty"`
+	ExclusiveMinimum interface{} `json:"exclusiveMinimum,omitempty"` // bool, or number since OpenAPI 3.1
+	ExclusiveMaximum interface{} `json:"exclusiveMaximum,omitempty"` // bool, or number since OpenAPI 3.1
+	MinLength        *uint64     `json:"minLength,omitempty"`
+	MaxLength        *uint64     `json:"maxLength,omitempty"`
+	Pattern          string      `json:"pattern,omitempty"`
+	MinItems         *uint64     `json:"minItems,omitempty"`
+	MaxItems         *uint64     `json:"maxItems,omitempty"`
+	UniqueItems      bool        `json:"uniqueItems,omitempty"`
+	MinProperties    *uint64     `json:"minProperties,omitempty"`
+	MaxProperties    *uint64     `json:"maxProperties,omitempty"`
+
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"` // *SchemaObject, or true for any value
+	PropertyNames        *SchemaObject `json:"propertyNames,omitempty"`        // OpenAPI 3.1
+	GoKeyType            string        `json:"x-go-key-type,omitempty"`        // key type of a Go map other than string
+
+	AllOf         []*SchemaObject      `json:"allOf,omitempty"`
+	OneOf         []*SchemaObject      `json:"oneOf,omitempty"`
+	AnyOf         []*SchemaObject      `json:"anyOf,omitempty"`
+	Discriminator *DiscriminatorObject `json:"discriminator,omitempty"`
 
 	// Ref is used when SchemaObject is as a ReferenceObject
 	Ref string `json:"$ref,omitempty"`
 
 	// Title
 	// MultipleOf
-	// Maximum
-	// ExclusiveMaximum
-	// Minimum
-	// ExclusiveMinimum
-	// MaxLength
-	// MinLength
-	// Pattern
-	// MaxItems
-	// MinItems
-	// UniqueItems
-	// MaxProperties
-	// MinProperties
-	// Enum
-	// AllOf
-	// OneOf
-	// AnyOf
 	// Not
-	// AdditionalProperties
 	// Description
 	// Default
-	// Nullable
 	// ReadOnly
 	// WriteOnly
 	// XML
 	// ExternalDocs
 }
 
+// MarshalJSON writes Types as the type keyword when it is set.
+func (s SchemaObject) MarshalJSON() ([]byte, error) {
+	type schemaObject SchemaObject
+	if len(s.Types) == 0 {
+		return json.Marshal(schemaObject(s))
+	}
+	return json.Marshal(struct {
+		Type []string `json:"type"`
+		schemaObject
+	}{s.Types, schemaObject(s)})
+}
+
+type DiscriminatorObject struct {
+	PropertyName string            `json:"propertyName"` // Required
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
 type ResponsesObject map[string]*ResponseObject // [status]ResponseObject
 
 type ResponseObject struct {
@@ -192,8 +251,8 @@ type ResponseObject struct {
 }
 
 type HeaderObject struct {
-	Description string `json:"description,omitempty"`
-	Type        string `json:"type,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Schema      *SchemaObject `json:"schema,omitempty"`
 
 	// Ref is used when HeaderObject is as a ReferenceObject
 	Ref string `json:"$ref,omitempty"`
@@ -263,3 +322,109 @@ type SecuritySchemeOauthFlowObject struct {
 	TokenUrl         string            `json:"tokenUrl,omitempty"`
 	Scopes           map[string]string `json:"scopes"`
 }
+
+// WalkSchemaObjects calls fn once for every schema object reachable from the document.
+func (o *OpenAPIObject) WalkSchemaObjects(fn func(*SchemaObject)) {
+	visited := map[*SchemaObject]struct{}{}
+	var walkSchema func(s *SchemaObject)
+	walkSchema = func(s *SchemaObject) {
+		if s == nil {
+			return
+		}
+		if _, ok := visited[s]; ok {
+			return
+		}
+		visited[s] = struct{}{}
+		fn(s)
+		walkSchema(s.Items)
+		for _, allOfSchema := range s.AllOf {
+			walkSchema(allOfSchema)
+		}
+		for _, oneOfSchema := range s.OneOf {
+			walkSchema(oneOfSchema)
+		}
+		for _, anyOfSchema := range s.AnyOf {
+			walkSchema(anyOfSchema)
+		}
+		if additionalProperties, ok := s.AdditionalProperties.(*SchemaObject); ok {
+			walkSchema(additionalProperties)
+		}
+		if s.Properties != nil {
+			for _, key := range s.Properties.Keys() {
+				v, _ := s.Properties.Get(key)
+				if propertySchema, ok := v.(*SchemaObject); ok {
+					walkSchema(propertySchema)
+				}
+			}
+		}
+	}
+	walkOperation := func(operation *OperationObject) {
+		if operation == nil {
+			return
+		}
+		for i := range operation.Parameters {
+			walkSchema(operation.Parameters[i].Schema)
+		}
+		if operation.RequestBody != nil {
+			for _, mediaType := range operation.RequestBody.Content {
+				walkSchema(&mediaType.Schema)
+			}
+		}
+		for _, response := range operation.Responses {
+			for _, header := range response.Headers {
+				walkSchema(header.Schema)
+			}
+			for _, mediaType := range response.Content {
+				walkSchema(&mediaType.Schema)
+			}
+		}
+	}
+	walkPathItem := func(pathItem *PathItemObject) {
+		for _, operation := range pathItem.Operations() {
+			walkOperation(operation)
+		}
+	}
+
+	for _, schema := range o.Components.Schemas {
+		walkSchema(schema)
+	}
+	for _, pathItem := range o.Paths {
+		walkPathItem(pathItem)
+	}
+	for _, pathItem := range o.Webhooks {
+		walkPathItem(pathItem)
+	}
+}
+
+// SetOperation sets the operation of the path item for the HTTP method.
+func (p *PathItemObject) SetOperation(method string, operation *OperationObject) {
+	switch strings.ToUpper(method) {
+	case http.MethodGet:
+		p.Get = operation
+	case http.MethodPost:
+		p.Post = operation
+	case http.MethodPatch:
+		p.Patch = operation
+	case http.MethodPut:
+		p.Put = operation
+	case http.MethodDelete:
+		p.Delete = operation
+	case http.MethodOptions:
+		p.Options = operation
+	case http.MethodHead:
+		p.Head = operation
+	case http.MethodTrace:
+		p.Trace = operation
+	}
+}
+
+// Operations returns the operations of the path item which are set.
+func (p *PathItemObject) Operations() []*OperationObject {
+	operations := []*OperationObject{}
+	for _, operation := range []*OperationObject{p.Get, p.Post, p.Patch, p.Put, p.Delete, p.Options, p.Head, p.Trace} {
+		if operation != nil {
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
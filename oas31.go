@@ -0,0 +1,50 @@
+package main
+
+// convertToOpenAPI31 rewrites the OpenAPI 3.0 keywords of the parsed document
+// into the JSON Schema 2020-12 keywords used by OpenAPI 3.1.
+func (p *parser) convertToOpenAPI31() {
+	p.OpenAPI.JSONSchemaDialect = JSONSchemaDialect202012
+
+	p.OpenAPI.WalkSchemaObjects(func(schema *SchemaObject) {
+		// nullable is replaced by "null" in the type list
+		if schema.Nullable {
+			if len(schema.AllOf) == 1 && schema.AllOf[0].Ref != "" {
+				// and a nullable reference is any of the reference and null,
+				// without the type which nullable of OpenAPI 3.0 needs
+				schema.AnyOf = []*SchemaObject{schema.AllOf[0], {Type: "null"}}
+				schema.AllOf = nil
+				schema.Type = ""
+			} else if schema.Type != "" {
+				schema.Types = []string{schema.Type, "null"}
+			}
+			schema.Nullable = false
+		}
+
+		// example is deprecated in favor of examples
+		if schema.Example != nil {
+			schema.Examples = append(schema.Examples, schema.Example)
+			schema.Example = nil
+		}
+
+		// exclusiveMinimum and exclusiveMaximum are numbers instead of bool
+		if schema.ExclusiveMinimum == true && schema.Minimum != nil {
+			schema.ExclusiveMinimum = *schema.Minimum
+			schema.Minimum = nil
+		}
+		if schema.ExclusiveMaximum == true && schema.Maximum != nil {
+			schema.ExclusiveMaximum = *schema.Maximum
+			schema.Maximum = nil
+		}
+
+		// keys of a Go map with integer keys are integers in string form
+		if goTypesOASTypes[schema.GoKeyType] == "integer" && schema.PropertyNames == nil {
+			schema.PropertyNames = &SchemaObject{Pattern: "^-?[0-9]+$"}
+		}
+
+		// an enum with a single value is a const
+		if len(schema.Enum) == 1 {
+			schema.Const = schema.Enum[0]
+			schema.Enum = nil
+		}
+	})
+}
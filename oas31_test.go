@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertToOpenAPI31(t *testing.T) {
+	float64Ptr := func(n float64) *float64 { return &n }
+	ref := &SchemaObject{Ref: "#/components/schemas/User"}
+	tests := []struct {
+		name   string
+		schema SchemaObject
+		want   SchemaObject
+	}{
+		{
+			name:   "nullable type",
+			schema: SchemaObject{Type: "string", Nullable: true},
+			want:   SchemaObject{Type: "string", Types: []string{"string", "null"}},
+		},
+		{
+			name:   "nullable reference",
+			schema: SchemaObject{Nullable: true, AllOf: []*SchemaObject{ref}},
+			want:   SchemaObject{AnyOf: []*SchemaObject{ref, {Type: "null"}}},
+		},
+		{
+			name:   "nullable reference with type",
+			schema: SchemaObject{Type: "object", Nullable: true, AllOf: []*SchemaObject{ref}},
+			want:   SchemaObject{AnyOf: []*SchemaObject{ref, {Type: "null"}}},
+		},
+		{
+			name:   "example",
+			schema: SchemaObject{Type: "integer", Example: 1},
+			want:   SchemaObject{Type: "integer", Examples: []interface{}{1}},
+		},
+		{
+			name:   "exclusive minimum and maximum",
+			schema: SchemaObject{Type: "number", Minimum: float64Ptr(0), ExclusiveMinimum: true, Maximum: float64Ptr(9), ExclusiveMaximum: true},
+			want:   SchemaObject{Type: "number", ExclusiveMinimum: float64(0), ExclusiveMaximum: float64(9)},
+		},
+		{
+			name:   "inclusive minimum",
+			schema: SchemaObject{Type: "number", Minimum: float64Ptr(0)},
+			want:   SchemaObject{Type: "number", Minimum: float64Ptr(0)},
+		},
+		{
+			name:   "map with integer keys",
+			schema: SchemaObject{Type: "object", GoKeyType: "int64"},
+			want:   SchemaObject{Type: "object", GoKeyType: "int64", PropertyNames: &SchemaObject{Pattern: "^-?[0-9]+$"}},
+		},
+		{
+			name:   "single enum value",
+			schema: SchemaObject{Type: "string", Enum: []interface{}{"active"}},
+			want:   SchemaObject{Type: "string", Const: "active"},
+		},
+		{
+			name:   "enum values",
+			schema: SchemaObject{Type: "string", Enum: []interface{}{"active", "banned"}},
+			want:   SchemaObject{Type: "string", Enum: []interface{}{"active", "banned"}},
+		},
+	}
+	for _, tt := range tests {
+		schema := tt.schema
+		p := &parser{}
+		p.OpenAPI.Components.Schemas = map[string]*SchemaObject{"Test": &schema}
+		p.convertToOpenAPI31()
+		if p.OpenAPI.JSONSchemaDialect != JSONSchemaDialect202012 {
+			t.Errorf("%s: jsonSchemaDialect = %q, want %q", tt.name, p.OpenAPI.JSONSchemaDialect, JSONSchemaDialect202012)
+		}
+		if !reflect.DeepEqual(schema, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, schema, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+)
+
+var oasPrimitiveTypes = map[string]struct{}{
+	"string":  {},
+	"number":  {},
+	"integer": {},
+	"boolean": {},
+	"object":  {},
+}
+
+// parseTypeOverrideTag returns the schema which replaces the schema of the Go type of a field, or nil.
+// It is set by the goas tag, like goas:"type=string,format=date", goas:"type=[]integer" or goas:"ref=Money",
+// or by the swaggertype tag of swaggo, like swaggertype:"primitive,integer" or swaggertype:"array,number".
+func (p *parser) parseTypeOverrideTag(pkgPath, pkgName string, astField *ast.Field) (*SchemaObject, error) {
+	if astField.Tag == nil {
+		return nil, nil
+	}
+	astFieldTag := reflect.StructTag(strings.Trim(astField.Tag.Value, "`"))
+	typeName, format, ref := "", "", ""
+	for _, v := range strings.Split(astFieldTag.Get("goas"), ",") {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "type":
+			typeName = strings.TrimSpace(kv[1])
+		case "format":
+			format = strings.TrimSpace(kv[1])
+		case "ref":
+			ref = strings.TrimSpace(kv[1])
+		}
+	}
+
+	if ref != "" {
+		id, err := p.registerType(pkgPath, pkgName, ref)
+		if err != nil {
+			return nil, err
+		}
+		schema := &SchemaObject{ID: id, Ref: addSchemaRefLinkPrefix(id)}
+		if knownSchema, ok := p.KnownIDSchema[id]; ok {
+			schema.Type = knownSchema.Type
+		}
+		return schema, nil
+	} else if typeName != "" {
+		if strings.HasPrefix(typeName, "[]") {
+			items, err := getPrimitiveSchemaObject(typeName[2:], format)
+			if err != nil {
+				return nil, err
+			}
+			return &SchemaObject{Type: "array", Items: items}, nil
+		}
+		return getPrimitiveSchemaObject(typeName, format)
+	}
+
+	tag := astFieldTag.Get("swaggertype")
+	if tag == "" {
+		return nil, nil
+	}
+	values := strings.Split(tag, ",")
+	switch values[0] {
+	case "primitive":
+		if len(values) != 2 {
+			return nil, fmt.Errorf("parseTypeOverrideTag: swaggertype %s needs a type", tag)
+		}
+		return getPrimitiveSchemaObject(values[1], "")
+	case "array":
+		if len(values) != 2 {
+			return nil, fmt.Errorf("parseTypeOverrideTag: swaggertype %s needs a type of the items", tag)
+		}
+		items, err := getPrimitiveSchemaObject(values[1], "")
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaObject{Type: "array", Items: items}, nil
+	case "object":
+		schema := &SchemaObject{Type: "object"}
+		if len(values) == 2 {
+			additionalProperties, err := getPrimitiveSchemaObject(values[1], "")
+			if err != nil {
+				return nil, err
+			}
+			schema.AdditionalProperties = additionalProperties
+		}
+		return schema, nil
+	}
+	return getPrimitiveSchemaObject(values[0], "")
+}
+
+// getPrimitiveSchemaObject returns the schema of an OpenAPI type, like integer, or of a basic Go type, like int64.
+func getPrimitiveSchemaObject(typeName, format string) (*SchemaObject, error) {
+	schema := &SchemaObject{}
+	if _, ok := oasPrimitiveTypes[typeName]; ok {
+		schema.Type = typeName
+	} else if isGoTypeOASType(typeName) {
+		schema.Type = goTypesOASTypes[typeName]
+		schema.Format = goTypesOASFormats[typeName]
+	} else {
+		return nil, fmt.Errorf("getPrimitiveSchemaObject: unknown type %s", typeName)
+	}
+	if format != "" {
+		schema.Format = format
+	}
+	return schema, nil
+}
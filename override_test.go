@@ -0,0 +1,47 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"reflect"
+	"testing"
+)
+
+func TestParseTypeOverrideTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    *SchemaObject
+		wantErr bool
+	}{
+		{``, nil, false},
+		{`json:"name"`, nil, false},
+		{`goas:"type=string,format=date"`, &SchemaObject{Type: "string", Format: "date"}, false},
+		{`goas:"type=int64"`, &SchemaObject{Type: "integer", Format: "int64"}, false},
+		{`goas:"type=[]integer"`, &SchemaObject{Type: "array", Items: &SchemaObject{Type: "integer"}}, false},
+		{`goas:"nullable"`, nil, false},
+		{`goas:"type=strnig"`, nil, true},
+		{`goas:"type=[]strnig"`, nil, true},
+		{`swaggertype:"primitive,integer"`, &SchemaObject{Type: "integer"}, false},
+		{`swaggertype:"array,number"`, &SchemaObject{Type: "array", Items: &SchemaObject{Type: "number"}}, false},
+		{`swaggertype:"object"`, &SchemaObject{Type: "object"}, false},
+		{`swaggertype:"object,string"`, &SchemaObject{Type: "object", AdditionalProperties: &SchemaObject{Type: "string"}}, false},
+		{`swaggertype:"string"`, &SchemaObject{Type: "string"}, false},
+		{`swaggertype:"primitive"`, nil, true},
+		{`swaggertype:"array"`, nil, true},
+		{`swaggertype:"primitive,strnig"`, nil, true},
+	}
+	for _, tt := range tests {
+		p := &parser{}
+		astField := &ast.Field{Names: []*ast.Ident{ast.NewIdent("Field")}, Type: ast.NewIdent("string")}
+		if tt.tag != "" {
+			astField.Tag = &ast.BasicLit{Kind: token.STRING, Value: "`" + tt.tag + "`"}
+		}
+		got, err := p.parseTypeOverrideTag("", "", astField)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTypeOverrideTag(%s) error = %v, want error %v", tt.tag, err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTypeOverrideTag(%s) = %+v, want %+v", tt.tag, got, tt.want)
+		}
+	}
+}
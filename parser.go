@@ -16,6 +16,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/iancoleman/orderedmap"
@@ -41,7 +42,15 @@ type parser struct {
 	KnownPathPkg  map[string]*pkg
 	KnownIDSchema map[string]*SchemaObject
 
+	KnownOperationIDs map[string]string
+	KnownInstanceIDs  map[string]string // instantiations with the packages of their type arguments, to their component IDs
+
 	TypeSpecs               map[string]map[string]*ast.TypeSpec
+	TypeMethods             map[string]map[string][]string
+	FuncScope               string                   // "Func" or "Recv@Func" of the handler func being parsed, for its local types
+	TypeArgs                map[string]string        // type parameters of the generic type being parsed, to their resolved type arguments
+	TypeMappings            map[string]*SchemaObject // fully qualified types, like github.com/acme/core/money.Amount, to their schemas
+	MarshalerFallback       *SchemaObject            // schema of the types implementing json.Marshaler without @Schema
 	PkgPathAstPkgCache      map[string]map[string]*ast.Package
 	PkgNameImportedPkgAlias map[string]map[string][]string
 
@@ -53,18 +62,31 @@ type pkg struct {
 	Path string
 }
 
-func newParser(modulePath, mainFilePath, handlerPath string, debug bool) (*parser, error) {
+func newParser(modulePath, mainFilePath, handlerPath, openAPIVersion, typeMappingPath, marshalerFallback string, debug bool) (*parser, error) {
 	p := &parser{
 		KnownPkgs:               []pkg{},
 		KnownNamePkg:            map[string]*pkg{},
 		KnownPathPkg:            map[string]*pkg{},
 		KnownIDSchema:           map[string]*SchemaObject{},
+		KnownOperationIDs:       map[string]string{},
+		KnownInstanceIDs:        map[string]string{},
 		TypeSpecs:               map[string]map[string]*ast.TypeSpec{},
+		TypeMethods:             map[string]map[string][]string{},
 		PkgPathAstPkgCache:      map[string]map[string]*ast.Package{},
 		PkgNameImportedPkgAlias: map[string]map[string][]string{},
 		Debug:                   debug,
 	}
-	p.OpenAPI.OpenAPI = OpenAPIVersion
+	switch openAPIVersion {
+	case "", "3.0", OpenAPIVersion30:
+		p.OpenAPI.OpenAPI = OpenAPIVersion30
+	case "3.1", OpenAPIVersion31:
+		p.OpenAPI.OpenAPI = OpenAPIVersion31
+	case SwaggerVersion20:
+		// parsed as OpenAPI 3.0 and converted before output
+		p.OpenAPI.OpenAPI = SwaggerVersion20
+	default:
+		return nil, fmt.Errorf("unsupported openapi version %s", openAPIVersion)
+	}
 	p.OpenAPI.Paths = make(PathsObject)
 	p.OpenAPI.Security = []map[string][]string{}
 	p.OpenAPI.Components.Schemas = make(map[string]*SchemaObject)
@@ -171,47 +193,94 @@ func newParser(modulePath, mainFilePath, handlerPath string, debug bool) (*parse
 	p.HandlerPath = handlerPath
 	p.debugf("handler path: %s", p.HandlerPath)
 
+	if typeMappingPath != "" {
+		p.TypeMappings, err = parseTypeMappingFile(typeMappingPath)
+		if err != nil {
+			return nil, err
+		}
+		p.debugf("type mappings: %d", len(p.TypeMappings))
+	}
+	if marshalerFallback != "" {
+		p.MarshalerFallback = &SchemaObject{}
+		err = unmarshalYaml([]byte(marshalerFallback), p.MarshalerFallback)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse marshaler fallback %s: %s", marshalerFallback, err)
+		}
+	}
+
 	return p, nil
 }
 
-func (p *parser) CreateOASFile(path string) error {
-	// parse basic info
-	err := p.parseInfo()
+func (p *parser) CreateOASFile(path, format string) error {
+	if format == "" {
+		format = OutputFormatJson
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			format = OutputFormatYaml
+		}
+	}
+	format = strings.ToLower(format)
+	if format != OutputFormatJson && format != OutputFormatYaml {
+		return fmt.Errorf("unsupported output format %s", format)
+	}
+
+	err := p.parse()
 	if err != nil {
 		return err
 	}
 
-	// parse sub-package
-	err = p.parseModule()
+	var document interface{} = p.OpenAPI
+	switch p.OpenAPI.OpenAPI {
+	case OpenAPIVersion31:
+		p.convertToOpenAPI31()
+		document = p.OpenAPI
+	case SwaggerVersion20:
+		document = p.convertToSwagger2()
+	}
+
+	fd, err := os.Create(path)
 	if err != nil {
-		return err
+		return fmt.Errorf("Can not create the file %s: %v", path, err)
 	}
+	defer fd.Close()
 
-	// parse go.mod info
-	err = p.parseGoMod()
+	output, err := json.MarshalIndent(document, "", "  ")
 	if err != nil {
 		return err
 	}
+	if format == OutputFormatYaml {
+		output, err = jsonToYaml(output)
+		if err != nil {
+			return err
+		}
+	}
+	_, err = fd.WriteString(string(output))
 
-	// parse APIs info
-	err = p.parseAPIs()
+	return err
+}
+
+// parse parses the module into the OpenAPI 3.0 document of the parser.
+func (p *parser) parse() error {
+	// parse basic info
+	err := p.parseInfo()
 	if err != nil {
 		return err
 	}
 
-	fd, err := os.Create(path)
+	// parse sub-package
+	err = p.parseModule()
 	if err != nil {
-		return fmt.Errorf("Can not create the file %s: %v", path, err)
+		return err
 	}
-	defer fd.Close()
 
-	output, err := json.MarshalIndent(p.OpenAPI, "", "  ")
+	// parse go.mod info
+	err = p.parseGoMod()
 	if err != nil {
 		return err
 	}
-	_, err = fd.WriteString(string(output))
 
-	return err
+	// parse APIs info
+	return p.parseAPIs()
 }
 
 func (p *parser) parseInfo() error {
@@ -275,11 +344,7 @@ func (p *parser) parseInfo() error {
 					s := ServerObject{URL: fields[0], Description: value[len(fields[0]):]}
 					p.OpenAPI.Servers = append(p.OpenAPI.Servers, s)
 				case "@security":
-					fields := strings.Split(value, " ")
-					security := map[string][]string{
-						fields[0]: fields[1:],
-					}
-					p.OpenAPI.Security = append(p.OpenAPI.Security, security)
+					p.OpenAPI.Security = append(p.OpenAPI.Security, parseSecurityRequirement(value))
 				case "@securityscheme":
 					fields := strings.Split(value, " ")
 
@@ -551,6 +616,7 @@ func (p *parser) parseTypeSpecs() error {
 		_, ok := p.TypeSpecs[pkgName]
 		if !ok {
 			p.TypeSpecs[pkgName] = map[string]*ast.TypeSpec{}
+			p.TypeMethods[pkgName] = map[string][]string{}
 		}
 		astPkgs, err := p.getPkgAst(pkgPath)
 		if err != nil {
@@ -563,10 +629,17 @@ func (p *parser) parseTypeSpecs() error {
 						// find type declaration
 						for _, astSpec := range astGenDeclaration.Specs {
 							if typeSpec, ok := astSpec.(*ast.TypeSpec); ok {
+								// the doc of `type T ...` belongs to the declaration
+								if typeSpec.Doc == nil && len(astGenDeclaration.Specs) == 1 {
+									typeSpec.Doc = astGenDeclaration.Doc
+								}
 								p.TypeSpecs[pkgName][typeSpec.Name.String()] = typeSpec
 							}
 						}
 					} else if astFuncDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok {
+						if recvTypeName := getFuncRecvTypeName(astFuncDeclaration); recvTypeName != "" {
+							p.TypeMethods[pkgName][recvTypeName] = append(p.TypeMethods[pkgName][recvTypeName], astFuncDeclaration.Name.String())
+						}
 						// find type declaration in func, method
 						if astFuncDeclaration.Doc != nil && astFuncDeclaration.Doc.List != nil && astFuncDeclaration.Body != nil {
 							funcName := astFuncDeclaration.Name.String()
@@ -581,12 +654,7 @@ func (p *parser) parseTypeSpecs() error {
 													continue
 												}
 												// type in method
-												var recvTypeName string
-												if astStarExpr, ok := astFuncDeclaration.Recv.List[0].Type.(*ast.StarExpr); ok {
-													recvTypeName = fmt.Sprintf("%s", astStarExpr.X)
-												} else if astIdent, ok := astFuncDeclaration.Recv.List[0].Type.(*ast.Ident); ok {
-													recvTypeName = astIdent.String()
-												}
+												recvTypeName := getFuncRecvTypeName(astFuncDeclaration)
 												p.TypeSpecs[pkgName][strings.Join([]string{recvTypeName, funcName, typeSpec.Name.String()}, "@")] = typeSpec
 											}
 										}
@@ -618,7 +686,7 @@ func (p *parser) parsePaths() error {
 				for _, astDeclaration := range astFile.Decls {
 					if astFuncDeclaration, ok := astDeclaration.(*ast.FuncDecl); ok {
 						if astFuncDeclaration.Doc != nil && astFuncDeclaration.Doc.List != nil {
-							err = p.parseOperation(pkgPath, pkgName, astFuncDeclaration.Doc.List)
+							err = p.parseOperation(pkgPath, pkgName, astFuncDeclaration)
 							if err != nil {
 								return err
 							}
@@ -632,7 +700,7 @@ func (p *parser) parsePaths() error {
 	return nil
 }
 
-func (p *parser) parseOperation(pkgPath, pkgName string, astComments []*ast.Comment) error {
+func (p *parser) parseOperation(pkgPath, pkgName string, astFuncDeclaration *ast.FuncDecl) error {
 	operation := &OperationObject{
 		Responses: map[string]*ResponseObject{},
 	}
@@ -644,10 +712,46 @@ func (p *parser) parseOperation(pkgPath, pkgName string, astComments []*ast.Comm
 		return nil
 	}
 	var err error
-	for _, astComment := range astComments {
+	var routed bool
+
+	// types declared in the handler func can be used by its comments
+	p.FuncScope = astFuncDeclaration.Name.String()
+	if recvTypeName := getFuncRecvTypeName(astFuncDeclaration); recvTypeName != "" {
+		p.FuncScope = recvTypeName + "@" + p.FuncScope
+	}
+	defer func() {
+		p.FuncScope = ""
+	}()
+
+	// media types apply to all the @Param and @Success comments, wherever they are
+	for _, astComment := range astFuncDeclaration.Doc.List {
 		comment := strings.TrimSpace(strings.TrimLeft(astComment.Text, "/"))
 		if len(comment) == 0 {
-			return nil
+			break
+		}
+		attribute := strings.Fields(comment)[0]
+		switch strings.ToLower(attribute) {
+		case "@accept", "@produce":
+			mediaTypes, err := parseMediaTypes(comment[len(attribute):])
+			if err != nil {
+				return err
+			}
+			if strings.ToLower(attribute) == "@accept" {
+				operation.Accepts = append(operation.Accepts, mediaTypes...)
+			} else {
+				operation.Produces = append(operation.Produces, mediaTypes...)
+			}
+		}
+	}
+
+	// Go doc convention, a paragraph which begins with "Deprecated:"
+	deprecatedReason, deprecated := getDeprecatedReason(astFuncDeclaration.Doc)
+	operation.Deprecated = deprecated
+
+	for _, astComment := range astFuncDeclaration.Doc.List {
+		comment := strings.TrimSpace(strings.TrimLeft(astComment.Text, "/"))
+		if len(comment) == 0 {
+			break
 		}
 		attribute := strings.Fields(comment)[0]
 		switch strings.ToLower(attribute) {
@@ -655,10 +759,27 @@ func (p *parser) parseOperation(pkgPath, pkgName string, astComments []*ast.Comm
 			operation.Summary = strings.TrimSpace(comment[len(attribute):])
 		case "@description":
 			operation.Description = strings.Join([]string{operation.Description, strings.TrimSpace(comment[len(attribute):])}, " ")
+		case "@operationid":
+			operation.OperationID = strings.TrimSpace(comment[len(attribute):])
+		case "@deprecated":
+			operation.Deprecated = true
+			if reason := strings.TrimSpace(comment[len(attribute):]); reason != "" {
+				deprecatedReason = reason
+			}
+		case "@sunset":
+			operation.Sunset = strings.TrimSpace(comment[len(attribute):])
+			_, err = time.Parse("2006-01-02", operation.Sunset)
+			if err != nil {
+				err = fmt.Errorf("parseOperation: sunset must be a date like 2006-01-02, but got %s", operation.Sunset)
+			}
 		case "@param":
 			err = p.parseParamComment(pkgPath, pkgName, operation, strings.TrimSpace(comment[len(attribute):]))
+		case "@params":
+			err = p.parseParamStructComment(pkgPath, pkgName, operation, "", strings.TrimSpace(comment[len(attribute):]))
 		case "@success", "@failure":
 			err = p.parseResponseComment(pkgPath, pkgName, operation, strings.TrimSpace(comment[len(attribute):]))
+		case "@header":
+			err = p.parseHeaderComment(pkgPath, pkgName, operation, strings.TrimSpace(comment[len(attribute):]))
 		case "@resource", "@tag":
 			resource := strings.TrimSpace(comment[len(attribute):])
 			if resource == "" {
@@ -667,30 +788,78 @@ func (p *parser) parseOperation(pkgPath, pkgName string, astComments []*ast.Comm
 			if !isInStringList(operation.Tags, resource) {
 				operation.Tags = append(operation.Tags, resource)
 			}
+		case "@security":
+			err = p.parseSecurityComment(operation, strings.TrimSpace(comment[len(attribute):]))
+		case "@nosecurity":
+			operation.Security = &[]map[string][]string{}
 		case "@route", "@router":
 			err = p.parseRouteComment(operation, comment)
+			routed = true
+		case "@webhook":
+			err = p.parseWebhookComment(operation, comment)
+			routed = true
 		}
 		if err != nil {
 			return err
 		}
 	}
+	if !routed {
+		return nil
+	}
+
+	if deprecatedReason != "" {
+		operation.Description = strings.TrimSpace(strings.Join([]string{operation.Description, "Deprecated: " + deprecatedReason}, " "))
+	}
+
+	// operationId falls back to package name_[receiver type_]func name
+	funcName := astFuncDeclaration.Name.String()
+	recvTypeName := getFuncRecvTypeName(astFuncDeclaration)
+	if operation.OperationID == "" {
+		operation.OperationID = funcName
+		if recvTypeName != "" {
+			operation.OperationID = recvTypeName + "_" + funcName
+		}
+		operation.OperationID = getOperationIDPrefix(pkgName) + "_" + operation.OperationID
+	}
+	if recvTypeName != "" {
+		funcName = recvTypeName + "." + funcName
+	}
+	funcName = pkgName + "." + funcName
+	if knownFuncName, ok := p.KnownOperationIDs[operation.OperationID]; ok {
+		return fmt.Errorf("parseOperation: duplicate operationId %s of %s and %s, set another one by @OperationID", operation.OperationID, knownFuncName, funcName)
+	}
+	p.KnownOperationIDs[operation.OperationID] = funcName
+
 	return nil
 }
 
 func (p *parser) parseParamComment(pkgPath, pkgName string, operation *OperationObject, comment string) error {
-	// {name}  {in}  {goType}  {required}  {description}
-	// user    body  User      true        "Info of a user."
-	// f       file  ignored   true        "Upload a file."
-	re := regexp.MustCompile(`([-\w]+)[\s]+([\w]+)[\s]+([\w./\[\]]+)[\s]+([\w]+)[\s]+"([^"]+)"`)
+	// .  {in}   {goType}         [{required}]  [{description}]
+	// .  query  ListUsersFilter
+	re := regexp.MustCompile(`^\.[\s]+([\w]+)[\s]+([\w./\[\],]+)(?:[\s]+[\w]+)?(?:[\s]+"[^"]*")?$`)
+	if matches := re.FindStringSubmatch(comment); len(matches) == 3 {
+		switch matches[1] {
+		case "path", "query", "header", "cookie":
+		default:
+			return fmt.Errorf("parseParamComment: fields of %s can not be in %s", matches[2], matches[1])
+		}
+		return p.parseParamStructComment(pkgPath, pkgName, operation, matches[1], matches[2])
+	}
+
+	// {name}  {in}   {goType}  {required}  {description}       [{option}=]...
+	// user    body   User      true        "Info of a user."
+	// f       file   ignored   true        "Upload a file."
+	// ids     query  []int     true        "Ids of users."     style=form explode=false
+	re = regexp.MustCompile(`([-\w]+)[\s]+([\w]+)[\s]+([\w./\[\],{}=]+)[\s]+([\w]+)[\s]+"([^"]+)"(.*)`)
 	matches := re.FindStringSubmatch(comment)
-	if len(matches) != 6 {
+	if len(matches) != 7 {
 		return fmt.Errorf("parseParamComment can not parse param comment \"%s\"", comment)
 	}
 	name := matches[1]
 	in := matches[2]
 
-	re = regexp.MustCompile(`\[\w*\]`)
-	goType := re.ReplaceAllString(matches[3], "[]")
+	goType := normalizeGoType(matches[3])
+	pkgPath, pkgName, goType = p.resolveTypeAlias(pkgPath, pkgName, goType)
 
 	required := false
 	switch strings.ToLower(matches[4]) {
@@ -701,27 +870,32 @@ func (p *parser) parseParamComment(pkgPath, pkgName string, operation *Operation
 
 	// `file`, `form`
 	if in == "file" || in == "form" {
+		contentType := ContentTypeForm
+		if in == "form" && isInStringList(operation.Accepts, ContentTypeFormUrlencoded) && !isInStringList(operation.Accepts, ContentTypeForm) {
+			contentType = ContentTypeFormUrlencoded
+		}
 		if operation.RequestBody == nil {
 			operation.RequestBody = &RequestBodyObject{
-				Content: map[string]*MediaTypeObject{
-					ContentTypeForm: &MediaTypeObject{
-						Schema: SchemaObject{
-							Type:       "object",
-							Properties: orderedmap.New(),
-						},
-					},
-				},
+				Content:  map[string]*MediaTypeObject{},
 				Required: required,
 			}
 		}
+		if _, ok := operation.RequestBody.Content[contentType]; !ok {
+			operation.RequestBody.Content[contentType] = &MediaTypeObject{
+				Schema: SchemaObject{
+					Type:       "object",
+					Properties: orderedmap.New(),
+				},
+			}
+		}
 		if in == "file" {
-			operation.RequestBody.Content[ContentTypeForm].Schema.Properties.Set(name, &SchemaObject{
+			operation.RequestBody.Content[contentType].Schema.Properties.Set(name, &SchemaObject{
 				Type:        "string",
 				Format:      "binary",
 				Description: description,
 			})
 		} else if isGoTypeOASType(goType) {
-			operation.RequestBody.Content[ContentTypeForm].Schema.Properties.Set(name, &SchemaObject{
+			operation.RequestBody.Content[contentType].Schema.Properties.Set(name, &SchemaObject{
 				Type:        goTypesOASTypes[goType],
 				Format:      goTypesOASFormats[goType],
 				Description: description,
@@ -741,21 +915,19 @@ func (p *parser) parseParamComment(pkgPath, pkgName string, operation *Operation
 		if in == "path" {
 			parameterObject.Required = true
 		}
-		if goType == "time.Time" {
-			var err error
-			parameterObject.Schema, err = p.parseSchemaObject(pkgPath, pkgName, goType)
-			if err != nil {
-				p.debug("parseResponseComment cannot parse goType", goType)
-			}
-			operation.Parameters = append(operation.Parameters, parameterObject)
-		} else if isGoTypeOASType(goType) {
-			parameterObject.Schema = &SchemaObject{
-				Type:        goTypesOASTypes[goType],
-				Format:      goTypesOASFormats[goType],
-				Description: description,
-			}
-			operation.Parameters = append(operation.Parameters, parameterObject)
+		schema, err := p.parseParameterSchemaObject(pkgPath, pkgName, goType)
+		if err != nil {
+			return fmt.Errorf("parseParamComment: %s", err)
+		}
+		if isGoTypeOASType(goType) {
+			schema.Description = description
 		}
+		parameterObject.Schema = schema
+		err = p.parseParamOptions(&parameterObject, matches[6])
+		if err != nil {
+			return err
+		}
+		operation.Parameters = append(operation.Parameters, parameterObject)
 		return nil
 	}
 
@@ -766,50 +938,221 @@ func (p *parser) parseParamComment(pkgPath, pkgName string, operation *Operation
 		}
 	}
 
-	if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[]") || goType == "time.Time" {
-		schema, err := p.parseSchemaObject(pkgPath, pkgName, goType)
+	var schema SchemaObject
+	if strings.Contains(goType, "{") {
+		parsedSchema, err := p.parseOverrideSchemaObject(pkgPath, pkgName, goType)
 		if err != nil {
-			p.debug("parseResponseComment cannot parse goType", goType)
+			return err
 		}
-		operation.RequestBody.Content[ContentTypeJson] = &MediaTypeObject{
-			Schema: *schema,
+		schema = *parsedSchema
+	} else if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") || isWellKnownType(goType) {
+		parsedSchema, err := p.parseSchemaObject(pkgPath, pkgName, goType)
+		if err != nil {
+			return err
 		}
+		schema = *parsedSchema
 	} else {
-		typeName, err := p.registerType(pkgPath, pkgName, matches[3])
+		typeName, err := p.registerType(pkgPath, pkgName, goType)
 		if err != nil {
 			return err
 		}
 		if isBasicGoType(typeName) {
-			operation.RequestBody.Content[ContentTypeJson] = &MediaTypeObject{
-				Schema: SchemaObject{
-					Type: "string",
-				},
+			schema = SchemaObject{
+				Type: "string",
 			}
 		} else {
-			operation.RequestBody.Content[ContentTypeJson] = &MediaTypeObject{
-				Schema: SchemaObject{
-					Ref: addSchemaRefLinkPrefix(typeName),
-				},
+			schema = SchemaObject{
+				Ref: addSchemaRefLinkPrefix(typeName),
+			}
+		}
+	}
+
+	contentTypes := operation.Accepts
+	if len(contentTypes) == 0 {
+		contentTypes = []string{ContentTypeJson}
+	}
+	setContentSchema(operation.RequestBody.Content, contentTypes, schema)
+
+	return nil
+}
+
+// parseParamStructComment adds a parameter for each field of the struct type.
+// In and name of a parameter are decided by the path, query, form, header or cookie tag of the field.
+// The fields without these tags are in in, named by their property names, or ignored when in is empty.
+func (p *parser) parseParamStructComment(pkgPath, pkgName string, operation *OperationObject, in, goType string) error {
+	schema, ok := p.KnownIDSchema[p.getSchemaObjectID(pkgName, goType)]
+	if !ok {
+		var err error
+		schema, err = p.parseSchemaObject(pkgPath, pkgName, goType)
+		if err != nil {
+			return err
+		}
+	}
+	if schema.Properties == nil {
+		return fmt.Errorf("parseParamStructComment: %s has no fields", goType)
+	}
+
+	for _, propertyName := range schema.Properties.Keys() {
+		v, _ := schema.Properties.Get(propertyName)
+		propertySchema, ok := v.(*SchemaObject)
+		if !ok {
+			continue
+		}
+		fieldTag := reflect.StructTag(propertySchema.FieldTag)
+
+		tagKeys := []string{"path", "query", "form", "header", "cookie"}
+		if in != "" {
+			// the tag of in comes first, and the tag of another location moves the field there
+			tagKeys = append([]string{in}, tagKeys...)
+		}
+		parameterIn, name := "", ""
+		for _, tagKey := range tagKeys {
+			if tag := strings.Split(fieldTag.Get(tagKey), ",")[0]; tag != "" {
+				parameterIn, name = tagKey, tag
+				break
 			}
 		}
+		if parameterIn == "" {
+			parameterIn, name = in, propertyName
+		}
+		if parameterIn == "" || name == "-" {
+			continue
+		}
+		if parameterIn == "form" {
+			parameterIn = "query"
+		}
+
+		parameterSchema := *propertySchema
+		parameterSchema.Description = ""
+		parameterSchema.Example = nil
+		operation.Parameters = append(operation.Parameters, ParameterObject{
+			Name:        name,
+			In:          parameterIn,
+			Description: propertySchema.Description,
+			Required:    parameterIn == "path" || isInStringList(schema.Required, propertyName),
+			Example:     propertySchema.Example,
+			Schema:      &parameterSchema,
+		})
 	}
+	return nil
+}
+
+// parseParameterSchemaObject parses the schema of the types which path, query, header and cookie
+// parameters support, basic types, well-known types like time.Time, mapped types and slices of them.
+func (p *parser) parseParameterSchemaObject(pkgPath, pkgName, goType string) (*SchemaObject, error) {
+	pkgPath, pkgName, goType = p.resolveTypeAlias(pkgPath, pkgName, goType)
+	if mappedSchema, ok := p.getTypeMappingSchemaObject(pkgName, goType); ok {
+		return mappedSchema, nil
+	} else if isWellKnownType(goType) {
+		return p.parseSchemaObject(pkgPath, pkgName, goType)
+	} else if strings.HasPrefix(goType, "[]") {
+		itemsSchema, err := p.parseParameterSchemaObject(pkgPath, pkgName, goType[2:])
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaObject{
+			Type:  "array",
+			Items: itemsSchema,
+		}, nil
+	} else if isGoTypeOASType(goType) {
+		return &SchemaObject{
+			Type:   goTypesOASTypes[goType],
+			Format: goTypesOASFormats[goType],
+		}, nil
+	} else if !isBasicGoType(goType) && !strings.HasPrefix(goType, "map[") {
+		// a named type, like `type Status string`, has the schema of the registered type with its enum,
+		// which is copied since a parameter of Swagger 2.0 can not be a reference
+		id, err := p.registerType(pkgPath, pkgName, goType)
+		if err != nil {
+			return nil, err
+		}
+		if schema, ok := p.KnownIDSchema[id]; ok && schema.Type != "" && schema.Type != "object" {
+			parameterSchema := *schema
+			return &parameterSchema, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not supported by parameters", goType)
+}
 
+func (p *parser) parseParamOptions(parameterObject *ParameterObject, comment string) error {
+	// style=form explode=false allowReserved=true enum=asc,desc
+	for _, option := range strings.Fields(comment) {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("parseParamOptions: option must be key=value, but got %s", option)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "style":
+			switch kv[1] {
+			case "matrix", "label", "form", "simple", "spaceDelimited", "pipeDelimited", "deepObject":
+			default:
+				return fmt.Errorf("parseParamOptions: invalid style %s", kv[1])
+			}
+			parameterObject.Style = kv[1]
+		case "explode":
+			explode, err := strconv.ParseBool(kv[1])
+			if err != nil {
+				return fmt.Errorf("parseParamOptions: explode must be bool, but got %s", kv[1])
+			}
+			parameterObject.Explode = &explode
+		case "allowreserved":
+			allowReserved, err := strconv.ParseBool(kv[1])
+			if err != nil {
+				return fmt.Errorf("parseParamOptions: allowReserved must be bool, but got %s", kv[1])
+			}
+			parameterObject.AllowReserved = allowReserved
+		case "enum":
+			// the enum replaces the one of a named type, like `type Level int`
+			enumSchema := parameterObject.Schema
+			if enumSchema.Type == "array" && enumSchema.Items != nil {
+				enumSchema = enumSchema.Items
+			}
+			enums := []interface{}{}
+			for _, v := range strings.Split(kv[1], ",") {
+				value, err := parseValueByType(enumSchema.Type, v)
+				if err != nil {
+					return fmt.Errorf("parseParamOptions: invalid enum value %s of %s", v, enumSchema.Type)
+				}
+				enums = append(enums, value)
+			}
+			enumSchema.Enum = enums
+			enumSchema.EnumVarNames = nil
+		default:
+			return fmt.Errorf("parseParamOptions: unknown option %s", kv[0])
+		}
+	}
 	return nil
 }
 
 func (p *parser) parseResponseComment(pkgPath, pkgName string, operation *OperationObject, comment string) error {
-	// {status}  {jsonType}  {goType}     {description}
-	// 201       object      models.User  "User Model"
-	re := regexp.MustCompile(`([\d]+)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/\[\]]+)[^"]*(.*)?`)
+	// {status}  {description}
+	// 204       "User Deleted"
+	re := regexp.MustCompile(`^([\d]+|default)(?:[\s]+"([^"]*)")?$`)
 	matches := re.FindStringSubmatch(comment)
-	if len(matches) != 5 {
+	if len(matches) == 3 {
+		responseObject := &ResponseObject{
+			Description: matches[2],
+		}
+		if responseObject.Description == "" {
+			responseObject.Description = getResponseDescription(matches[1])
+		}
+		setResponseObject(operation, matches[1], responseObject)
+		return nil
+	}
+
+	// {status}  {jsonType}  {goType}     [{mediaType}]     {description}
+	// 201       object      models.User  "User Model"
+	// 200       object      models.User  json,xml          "User Model"
+	// default   object      models.Error "Unexpected Error"
+	re = regexp.MustCompile(`([\d]+|default)[\s]+([\w\{\}]+)[\s]+([\w\-\.\/\[\],{}=]+)[\s]*([^"]*)(.*)?`)
+	matches = re.FindStringSubmatch(comment)
+	if len(matches) != 6 {
 		return fmt.Errorf("parseResponseComment can not parse response comment \"%s\"", comment)
 	}
 
 	status := matches[1]
-	_, err := strconv.Atoi(matches[1])
-	if err != nil {
-		return fmt.Errorf("parseResponseComment: http status must be int, but got %s", status)
+	if !isResponseStatus(status) {
+		return fmt.Errorf("parseResponseComment: http status must be int or default, but got %s", status)
 	}
 	switch matches[2] {
 	case "object", "array", "{object}", "{array}":
@@ -819,42 +1162,207 @@ func (p *parser) parseResponseComment(pkgPath, pkgName string, operation *Operat
 	responseObject := &ResponseObject{
 		Content: map[string]*MediaTypeObject{},
 	}
-	responseObject.Description = strings.Trim(matches[4], "\"")
+	responseObject.Description = strings.Trim(matches[5], "\"")
+	if responseObject.Description == "" {
+		responseObject.Description = getResponseDescription(status)
+	}
 
-	re = regexp.MustCompile(`\[\w*\]`)
-	goType := re.ReplaceAllString(matches[3], "[]")
-	if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[]") {
-		schema, err := p.parseSchemaObject(pkgPath, pkgName, goType)
+	// media types of the response, then of the operation. Words which are not all media types are ignored,
+	// like an unquoted description
+	contentTypes, err := parseMediaTypes(matches[4])
+	if err != nil {
+		p.debugf("parseResponseComment ignores %s of response comment \"%s\"", strings.TrimSpace(matches[4]), comment)
+		contentTypes = nil
+	}
+	if len(contentTypes) == 0 {
+		contentTypes = operation.Produces
+	}
+
+	var schema SchemaObject
+	goType := normalizeGoType(matches[3])
+	pkgPath, pkgName, goType = p.resolveTypeAlias(pkgPath, pkgName, goType)
+	if isWellKnownType(goType) {
+		parsedSchema, err := p.parseSchemaObject(pkgPath, pkgName, goType)
 		if err != nil {
-			p.debug("parseResponseComment cannot parse goType", goType)
+			return err
+		}
+		schema = *parsedSchema
+		if len(contentTypes) == 0 {
+			contentTypes = []string{ContentTypeJson}
+		}
+	} else if strings.Contains(goType, "{") {
+		parsedSchema, err := p.parseOverrideSchemaObject(pkgPath, pkgName, goType)
+		if err != nil {
+			return err
 		}
-		responseObject.Content[ContentTypeJson] = &MediaTypeObject{
-			Schema: *schema,
+		schema = *parsedSchema
+		if len(contentTypes) == 0 {
+			contentTypes = []string{ContentTypeJson}
+		}
+	} else if strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[") {
+		parsedSchema, err := p.parseSchemaObject(pkgPath, pkgName, goType)
+		if err != nil {
+			return err
+		}
+		schema = *parsedSchema
+		if len(contentTypes) == 0 {
+			contentTypes = []string{ContentTypeJson}
 		}
 	} else {
-		typeName, err := p.registerType(pkgPath, pkgName, matches[3])
+		typeName, err := p.registerType(pkgPath, pkgName, goType)
 		if err != nil {
 			return err
 		}
 		if isBasicGoType(typeName) {
-			responseObject.Content[ContentTypeText] = &MediaTypeObject{
-				Schema: SchemaObject{
-					Type: "string",
-				},
+			schema = SchemaObject{
+				Type: "string",
+			}
+			if len(contentTypes) == 0 {
+				contentTypes = []string{ContentTypeText}
 			}
 		} else {
-			responseObject.Content[ContentTypeJson] = &MediaTypeObject{
+			schema = SchemaObject{
+				Ref: addSchemaRefLinkPrefix(typeName),
+			}
+			if len(contentTypes) == 0 {
+				contentTypes = []string{ContentTypeJson}
+			}
+		}
+	}
+	setContentSchema(responseObject.Content, contentTypes, schema)
+	setResponseObject(operation, status, responseObject)
+
+	return nil
+}
+
+func setResponseObject(operation *OperationObject, status string, responseObject *ResponseObject) {
+	if existingResponseObject, ok := operation.Responses[status]; ok {
+		// keep headers of @Header comments which come before
+		responseObject.Headers = existingResponseObject.Headers
+	}
+	operation.Responses[status] = responseObject
+}
+
+// getResponseDescription returns the description of a response without one, which is required.
+func getResponseDescription(status string) string {
+	if status == "default" {
+		return "Default response"
+	}
+	if code, err := strconv.Atoi(status); err == nil && http.StatusText(code) != "" {
+		return http.StatusText(code)
+	}
+	return "Response"
+}
+
+// isResponseStatus checks status is a HTTP status code or "default".
+func isResponseStatus(status string) bool {
+	if status == "default" {
+		return true
+	}
+	_, err := strconv.Atoi(status)
+	return err == nil
+}
+
+// setContentSchema sets the schema for each of the media types.
+func setContentSchema(content map[string]*MediaTypeObject, contentTypes []string, schema SchemaObject) {
+	for _, contentType := range contentTypes {
+		if contentType == ContentTypeOctetStream {
+			content[contentType] = &MediaTypeObject{
 				Schema: SchemaObject{
-					Ref: addSchemaRefLinkPrefix(typeName),
+					Type:   "string",
+					Format: "binary",
 				},
 			}
+			continue
+		}
+		content[contentType] = &MediaTypeObject{
+			Schema: schema,
 		}
 	}
-	operation.Responses[status] = responseObject
+}
+
+func (p *parser) parseHeaderComment(pkgPath, pkgName string, operation *OperationObject, comment string) error {
+	// {status}  {name}        {goType}  {description}
+	// 200       X-Rate-Limit  int       "Requests per hour"
+	re := regexp.MustCompile(`([\w]+)[\s]+([-\w]+)[\s]+([\w./\[\],]+)(?:[\s]+"([^"]*)")?`)
+	matches := re.FindStringSubmatch(comment)
+	if len(matches) != 5 {
+		return fmt.Errorf("parseHeaderComment can not parse header comment \"%s\"", comment)
+	}
+
+	status := matches[1]
+	if !isResponseStatus(status) {
+		return fmt.Errorf("parseHeaderComment: http status must be int or default, but got %s", status)
+	}
+	name := matches[2]
+
+	goType := normalizeGoType(matches[3])
+	pkgPath, pkgName, goType = p.resolveTypeAlias(pkgPath, pkgName, goType)
+	var schema *SchemaObject
+	var err error
+	if isGoTypeOASType(goType) {
+		schema = &SchemaObject{
+			Type:   goTypesOASTypes[goType],
+			Format: goTypesOASFormats[goType],
+		}
+	} else {
+		schema, err = p.parseSchemaObject(pkgPath, pkgName, goType)
+		if err != nil {
+			return err
+		}
+	}
+
+	responseObject, ok := operation.Responses[status]
+	if !ok {
+		// the description is replaced by @Success or @Failure
+		responseObject = &ResponseObject{
+			Description: getResponseDescription(status),
+		}
+		operation.Responses[status] = responseObject
+	}
+	if responseObject.Headers == nil {
+		responseObject.Headers = map[string]*HeaderObject{}
+	}
+	responseObject.Headers[name] = &HeaderObject{
+		Description: matches[4],
+		Schema:      schema,
+	}
 
 	return nil
 }
 
+func (p *parser) parseSecurityComment(operation *OperationObject, comment string) error {
+	// {scheme} {scope}... [&& {scheme} {scope}...]
+	// AuthorizationHeader read write
+	security := parseSecurityRequirement(comment)
+	if len(security) == 0 {
+		return fmt.Errorf("parseSecurityComment can not parse security comment \"%s\"", comment)
+	}
+	for scheme := range security {
+		if _, ok := p.OpenAPI.Components.SecuritySchemes[scheme]; !ok {
+			return fmt.Errorf("parseSecurityComment: unknown security scheme %s", scheme)
+		}
+	}
+	if operation.Security == nil {
+		operation.Security = &[]map[string][]string{}
+	}
+	*operation.Security = append(*operation.Security, security)
+	return nil
+}
+
+// parseSecurityRequirement parses "scheme scope... && scheme scope...", all the schemes are required together.
+func parseSecurityRequirement(value string) map[string][]string {
+	security := map[string][]string{}
+	for _, requirement := range strings.Split(value, "&&") {
+		fields := strings.Fields(requirement)
+		if len(fields) == 0 {
+			continue
+		}
+		security[fields[0]] = append([]string{}, fields[1:]...)
+	}
+	return security
+}
+
 func (p *parser) parseRouteComment(operation *OperationObject, comment string) error {
 	sourceString := strings.TrimSpace(comment[len("@Router"):])
 
@@ -869,25 +1377,32 @@ func (p *parser) parseRouteComment(operation *OperationObject, comment string) e
 	if !ok {
 		p.OpenAPI.Paths[matches[1]] = &PathItemObject{}
 	}
+	p.OpenAPI.Paths[matches[1]].SetOperation(matches[2], operation)
+
+	return nil
+}
+
+func (p *parser) parseWebhookComment(operation *OperationObject, comment string) error {
+	if p.OpenAPI.OpenAPI != OpenAPIVersion31 {
+		return fmt.Errorf("Webhook comment \"%s\" requires openapi version %s", comment, OpenAPIVersion31)
+	}
+	sourceString := strings.TrimSpace(comment[len("@Webhook"):])
+
+	// name [method]
+	re := regexp.MustCompile(`([\w\.\/\-{}]+)[^\[]+\[([^\]]+)`)
+	matches := re.FindStringSubmatch(sourceString)
+	if len(matches) != 3 {
+		return fmt.Errorf("Can not parse webhook comment \"%s\", skipped", comment)
+	}
 
-	switch strings.ToUpper(matches[2]) {
-	case http.MethodGet:
-		p.OpenAPI.Paths[matches[1]].Get = operation
-	case http.MethodPost:
-		p.OpenAPI.Paths[matches[1]].Post = operation
-	case http.MethodPatch:
-		p.OpenAPI.Paths[matches[1]].Patch = operation
-	case http.MethodPut:
-		p.OpenAPI.Paths[matches[1]].Put = operation
-	case http.MethodDelete:
-		p.OpenAPI.Paths[matches[1]].Delete = operation
-	case http.MethodOptions:
-		p.OpenAPI.Paths[matches[1]].Options = operation
-	case http.MethodHead:
-		p.OpenAPI.Paths[matches[1]].Head = operation
-	case http.MethodTrace:
-		p.OpenAPI.Paths[matches[1]].Trace = operation
+	if p.OpenAPI.Webhooks == nil {
+		p.OpenAPI.Webhooks = map[string]*PathItemObject{}
 	}
+	_, ok := p.OpenAPI.Webhooks[matches[1]]
+	if !ok {
+		p.OpenAPI.Webhooks[matches[1]] = &PathItemObject{}
+	}
+	p.OpenAPI.Webhooks[matches[1]].SetOperation(matches[2], operation)
 
 	return nil
 }
@@ -897,8 +1412,11 @@ func (p *parser) registerType(pkgPath, pkgName, typeName string) (string, error)
 
 	if isBasicGoType(typeName) {
 		registerTypeName = typeName
-	} else if _, ok := p.KnownIDSchema[genSchemeaObjectID(pkgName, typeName)]; ok {
-		return genSchemeaObjectID(pkgName, typeName), nil
+	} else if _, ok := p.KnownIDSchema[typeName]; ok {
+		// a schema ID, like a resolved type argument of a generic type
+		return typeName, nil
+	} else if id := p.getSchemaObjectID(pkgName, typeName); p.KnownIDSchema[id] != nil {
+		return id, nil
 	} else {
 		schemaObject, err := p.parseSchemaObject(pkgPath, pkgName, typeName)
 		if err != nil {
@@ -919,39 +1437,58 @@ func (p *parser) parseSchemaObject(pkgPath, pkgName, typeName string) (*SchemaOb
 	var schemaObject SchemaObject
 	var err error
 
+	// an alias, like `type Color = string`, has the schema of the type it denotes instead of a component
+	pkgPath, pkgName, typeName = p.resolveTypeAlias(pkgPath, pkgName, typeName)
+
 	// handler basic and some specific typeName
-	if strings.HasPrefix(typeName, "[]") {
+	if mappedSchema, ok := p.getTypeMappingSchemaObject(pkgName, typeName); ok {
+		p.KnownIDSchema[mappedSchema.ID] = mappedSchema
+		return mappedSchema, nil
+	} else if wellKnownSchema, ok := getWellKnownSchemaObject(typeName); ok {
+		return wellKnownSchema, nil
+	} else if strings.HasPrefix(typeName, "[]") {
 		schemaObject.Type = "array"
-		itemTypeName := typeName[2:]
-		schema, ok := p.KnownIDSchema[genSchemeaObjectID(pkgName, itemTypeName)]
+		itemPkgPath, itemPkgName, itemTypeName := p.resolveTypeAlias(pkgPath, pkgName, typeName[2:])
+		if _, _, ok := parseGenericType(itemTypeName); ok || p.KnownIDSchema[itemTypeName] != nil {
+			itemTypeID, err := p.registerType(itemPkgPath, itemPkgName, itemTypeName)
+			if err != nil {
+				return nil, err
+			}
+			schemaObject.Items = &SchemaObject{Ref: addSchemaRefLinkPrefix(itemTypeID)}
+			return &schemaObject, nil
+		}
+		schema, ok := p.KnownIDSchema[p.getSchemaObjectID(itemPkgName, itemTypeName)]
 		if ok {
 			schemaObject.Items = &SchemaObject{Ref: addSchemaRefLinkPrefix(schema.ID)}
 			return &schemaObject, nil
 		}
-		schemaObject.Items, err = p.parseSchemaObject(pkgPath, pkgName, itemTypeName)
+		schemaObject.Items, err = p.parseSchemaObject(itemPkgPath, itemPkgName, itemTypeName)
 		if err != nil {
 			return nil, err
 		}
 		return &schemaObject, nil
-	} else if strings.HasPrefix(typeName, "map[]") {
+	} else if keyTypeName, valueTypeName, ok := parseMapType(typeName); ok {
 		schemaObject.Type = "object"
-		itemTypeName := typeName[5:]
-		schema, ok := p.KnownIDSchema[genSchemeaObjectID(pkgName, itemTypeName)]
-		if ok {
-			schemaObject.Items = &SchemaObject{Ref: addSchemaRefLinkPrefix(schema.ID)}
-			return &schemaObject, nil
+		if keyTypeName != "string" {
+			schemaObject.GoKeyType = keyTypeName
 		}
-		schemaProperty, err := p.parseSchemaObject(pkgPath, pkgName, itemTypeName)
+		schemaObject.AdditionalProperties, err = p.parseMapValueSchemaObject(pkgPath, pkgName, valueTypeName)
 		if err != nil {
 			return nil, err
 		}
-		schemaObject.Properties = orderedmap.New()
-		schemaObject.Properties.Set("key", schemaProperty)
-		return &schemaObject, nil
-	} else if typeName == "time.Time" {
-		schemaObject.Type = "string"
-		schemaObject.Format = "date-time"
 		return &schemaObject, nil
+	} else if valueTypeName, ok := getSQLNullValueType(typeName); ok {
+		// like sql.NullString, the schema of the value which may be null
+		valueSchema, err := p.parseOverrideSchemaObject(pkgPath, pkgName, valueTypeName)
+		if err != nil {
+			return nil, err
+		}
+		// nullable only applies with a type in OpenAPI 3.0
+		if schema, ok := p.KnownIDSchema[trimeSchemaRefLinkPrefix(valueSchema.Ref)]; ok && valueSchema.Type == "" {
+			valueSchema.Type = schema.Type
+		}
+		setNullable(valueSchema)
+		return valueSchema, nil
 	} else if strings.HasPrefix(typeName, "interface{}") {
 		return &schemaObject, nil
 	} else if isGoTypeOASType(typeName) {
@@ -959,15 +1496,33 @@ func (p *parser) parseSchemaObject(pkgPath, pkgName, typeName string) (*SchemaOb
 		return &schemaObject, nil
 	}
 
+	// instantiated generic type, like Page[User], whose type arguments are resolved here
+	var typeArgs []string
+	if genericTypeName, genericTypeArgs, ok := parseGenericType(typeName); ok {
+		for _, typeArg := range genericTypeArgs {
+			resolvedTypeArg, err := p.resolveTypeArg(pkgPath, pkgName, typeArg)
+			if err != nil {
+				return nil, err
+			}
+			typeArgs = append(typeArgs, resolvedTypeArg)
+		}
+		typeName = genericTypeName
+	}
+
 	// handler other type
 	typeNameParts := strings.Split(typeName, ".")
 	if len(typeNameParts) == 1 {
-		typeSpec, exist = p.getTypeSpec(pkgPath, pkgName, typeName)
+		schemaObject.PkgName = pkgName
+		schemaObject.ID = p.getSchemaObjectID(pkgName, typeName)
+		specTypeName := typeName
+		if localTypeName := p.getLocalTypeName(pkgName, typeName); localTypeName != "" {
+			// a type declared in the handler func, like pkg.GetUser.Response
+			specTypeName = localTypeName
+		}
+		typeSpec, exist = p.getTypeSpec(pkgPath, pkgName, specTypeName)
 		if !exist {
 			log.Fatalf("Can not find definition of %s ast.TypeSpec. Current package %s", typeName, pkgName)
 		}
-		schemaObject.PkgName = pkgName
-		schemaObject.ID = genSchemeaObjectID(pkgName, typeName)
 		p.KnownIDSchema[schemaObject.ID] = &schemaObject
 	} else {
 		guessPkgName := strings.Join(typeNameParts[:len(typeNameParts)-1], "/")
@@ -1013,20 +1568,103 @@ func (p *parser) parseSchemaObject(pkgPath, pkgName, typeName string) (*SchemaOb
 		pkgPath, pkgName = guessPkgPath, guessPkgName
 	}
 
-	if astIdent, ok := typeSpec.Type.(*ast.Ident); ok {
-		_ = astIdent
-	} else if astStructType, ok := typeSpec.Type.(*ast.StructType); ok {
+	// type parameters are only visible in the declaration of the generic type
+	outerTypeArgs := p.TypeArgs
+	p.TypeArgs = nil
+	defer func() {
+		p.TypeArgs = outerTypeArgs
+	}()
+	if typeArgs != nil {
+		if typeSpec.TypeParams == nil || typeSpec.TypeParams.NumFields() != len(typeArgs) {
+			return nil, fmt.Errorf("parseSchemaObject: %s needs %d type arguments", typeName, typeSpec.TypeParams.NumFields())
+		}
+		delete(p.KnownIDSchema, schemaObject.ID)
+		// each instantiation is a component, like core.Page_User, which is named after the packages
+		// of the type arguments too when another instantiation has the name, like core.Page_example.com.dto.User
+		instanceID := genSchemeaObjectID(schemaObject.PkgName, typeSpec.Name.String())
+		qualifiedInstanceID := instanceID
+		p.TypeArgs = map[string]string{}
+		i := 0
+		for _, astField := range typeSpec.TypeParams.List {
+			for _, astName := range astField.Names {
+				p.TypeArgs[astName.Name] = typeArgs[i]
+				instanceID += "_" + getTypeArgName(typeArgs[i], false)
+				qualifiedInstanceID += "_" + getTypeArgName(typeArgs[i], true)
+				i++
+			}
+		}
+		if id, ok := p.KnownInstanceIDs[qualifiedInstanceID]; ok {
+			instanceID = id
+		} else {
+			if _, ok := p.KnownIDSchema[instanceID]; ok {
+				instanceID = qualifiedInstanceID
+			}
+			p.KnownInstanceIDs[qualifiedInstanceID] = instanceID
+		}
+		schemaObject.ID = instanceID
+		if schema, ok := p.KnownIDSchema[schemaObject.ID]; ok {
+			return schema, nil
+		}
+		p.KnownIDSchema[schemaObject.ID] = &schemaObject
+	}
+
+	ok, err := p.parseMarshalerSchemaObject(pkgPath, pkgName, &schemaObject, typeSpec)
+	if err != nil {
+		return nil, err
+	} else if ok {
+		return &schemaObject, nil
+	}
+
+	switch typeSpec.Type.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		// named type, like `type UserID int64` or `type Amount decimal.Decimal`, has the schema of its underlying type
+		err = p.parseUnderlyingSchemaObject(pkgPath, pkgName, &schemaObject, typeSpec)
+		if err != nil {
+			return nil, err
+		}
+		enums, varNames := p.getTypeEnums(pkgPath, typeSpec.Name.String())
+		if len(enums) != 0 {
+			schemaObject.Enum = enums
+			schemaObject.EnumVarNames = varNames
+		}
+		return &schemaObject, nil
+	case *ast.InterfaceType:
+		err = p.parseInterfaceSchemaObject(pkgPath, pkgName, &schemaObject, typeSpec)
+		if err != nil {
+			return nil, err
+		}
+		return &schemaObject, nil
+	}
+
+	if astStructType, ok := typeSpec.Type.(*ast.StructType); ok {
 		schemaObject.Type = "object"
 		if astStructType.Fields != nil {
-			p.parseSchemaPropertiesFromStructFields(pkgPath, pkgName, &schemaObject, astStructType.Fields.List)
+			err = p.parseSchemaPropertiesFromStructFields(pkgPath, pkgName, &schemaObject, astStructType.Fields.List)
+			if err != nil {
+				return nil, err
+			}
 		}
+	} else if wellKnownSchema, ok := getWellKnownSchemaObject(p.getTypeAsString(typeSpec.Type)); ok {
+		// like `type Blob []byte`
+		id, schemaPkgName := schemaObject.ID, schemaObject.PkgName
+		schemaObject = *wellKnownSchema
+		schemaObject.ID, schemaObject.PkgName = id, schemaPkgName
+	} else if anonymousSchema, err := p.parseAnonymousSchemaObject(pkgPath, pkgName, typeSpec.Type); err != nil {
+		return nil, err
+	} else if anonymousSchema != nil {
+		// like `type Rows []struct{...}`
+		id, schemaPkgName := schemaObject.ID, schemaObject.PkgName
+		schemaObject = *anonymousSchema
+		schemaObject.ID, schemaObject.PkgName = id, schemaPkgName
 	} else if astArrayType, ok := typeSpec.Type.(*ast.ArrayType); ok {
 		schemaObject.Type = "array"
 		schemaObject.Items = &SchemaObject{}
 		typeAsString := p.getTypeAsString(astArrayType.Elt)
-		typeAsString = strings.TrimLeft(typeAsString, "*")
-		if !isBasicGoType(typeAsString) {
-			schemaItemsSchemeaObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+		itemsPkgPath, itemsPkgName, typeAsString := p.resolveTypeAlias(pkgPath, pkgName, strings.TrimLeft(typeAsString, "*"))
+		if wellKnownSchema, ok := getWellKnownSchemaObject(typeAsString); ok {
+			schemaObject.Items = wellKnownSchema
+		} else if !isBasicGoType(typeAsString) {
+			schemaItemsSchemeaObjectID, err := p.registerType(itemsPkgPath, itemsPkgName, typeAsString)
 			if err != nil {
 				p.debug("parseSchemaObject parse array items err:", err)
 			} else {
@@ -1037,24 +1675,219 @@ func (p *parser) parseSchemaObject(pkgPath, pkgName, typeName string) (*SchemaOb
 		}
 	} else if astMapType, ok := typeSpec.Type.(*ast.MapType); ok {
 		schemaObject.Type = "object"
-		schemaObject.Properties = orderedmap.New()
-		propertySchema := &SchemaObject{}
-		schemaObject.Properties.Set("key", propertySchema)
+		keyTypeAsString := p.getTypeAsString(astMapType.Key)
+		if keyTypeAsString != "string" {
+			schemaObject.GoKeyType = keyTypeAsString
+		}
 		typeAsString := p.getTypeAsString(astMapType.Value)
 		typeAsString = strings.TrimLeft(typeAsString, "*")
-		if !isBasicGoType(typeAsString) {
-			schemaItemsSchemeaObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+		schemaObject.AdditionalProperties, err = p.parseMapValueSchemaObject(pkgPath, pkgName, typeAsString)
+		if err != nil {
+			p.debug("parseSchemaObject parse map values err:", err)
+			schemaObject.AdditionalProperties = &SchemaObject{}
+		}
+	}
+
+	return &schemaObject, nil
+}
+
+// parseMapValueSchemaObject returns the additionalProperties of a map with the value type,
+// which is true for interface{} values, and a reference for named types.
+func (p *parser) parseMapValueSchemaObject(pkgPath, pkgName, valueTypeName string) (interface{}, error) {
+	pkgPath, pkgName, valueTypeName = p.resolveTypeAlias(pkgPath, pkgName, valueTypeName)
+	if valueTypeName == "interface{}" {
+		return true, nil
+	} else if isGoTypeOASType(valueTypeName) || isWellKnownType(valueTypeName) ||
+		strings.HasPrefix(valueTypeName, "[]") || strings.HasPrefix(valueTypeName, "map[") {
+		return p.parseSchemaObject(pkgPath, pkgName, valueTypeName)
+	} else if isBasicGoType(valueTypeName) {
+		return &SchemaObject{}, nil
+	}
+
+	typeName, err := p.registerType(pkgPath, pkgName, valueTypeName)
+	if err != nil {
+		return nil, err
+	}
+	return &SchemaObject{Ref: addSchemaRefLinkPrefix(typeName)}, nil
+}
+
+// parseUnderlyingSchemaObject resolves the underlying type of the type spec recursively,
+// and copies its schema into schemaObject.
+func (p *parser) parseUnderlyingSchemaObject(pkgPath, pkgName string, schemaObject *SchemaObject, typeSpec *ast.TypeSpec) error {
+	typeAsString := p.getTypeAsString(typeSpec.Type)
+	typeAsString = strings.TrimLeft(typeAsString, "*")
+	if typeAsString == typeSpec.Name.String() {
+		return fmt.Errorf("parseUnderlyingSchemaObject: %s is defined by itself", typeAsString)
+	}
+	if isGoTypeOASType(typeAsString) {
+		schemaObject.Type = goTypesOASTypes[typeAsString]
+		return nil
+	} else if isBasicGoType(typeAsString) {
+		return nil
+	}
+
+	underlyingSchema, err := p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+	if err != nil {
+		return err
+	}
+	id, schemaPkgName := schemaObject.ID, schemaObject.PkgName
+	*schemaObject = *underlyingSchema
+	schemaObject.ID, schemaObject.PkgName = id, schemaPkgName
+	return nil
+}
+
+// parseOverrideSchemaObject parses a type of comment with property overrides, like Response{data=[]User,meta=PageMeta},
+// into the allOf of the type and an object of the overridden properties. Named types are referenced.
+func (p *parser) parseOverrideSchemaObject(pkgPath, pkgName, typeName string) (*SchemaObject, error) {
+	pkgPath, pkgName, typeName = p.resolveTypeAlias(pkgPath, pkgName, typeName)
+	if isWellKnownType(typeName) {
+		return p.parseSchemaObject(pkgPath, pkgName, typeName)
+	} else if strings.HasPrefix(typeName, "[]") {
+		items, err := p.parseOverrideSchemaObject(pkgPath, pkgName, typeName[2:])
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaObject{Type: "array", Items: items}, nil
+	}
+
+	baseTypeName, overrides, ok := parseTypeOverrides(typeName)
+	if !ok {
+		if isGoTypeOASType(typeName) || typeName == "interface{}" || strings.HasPrefix(typeName, "map[") {
+			return p.parseSchemaObject(pkgPath, pkgName, typeName)
+		}
+		typeID, err := p.registerType(pkgPath, pkgName, typeName)
+		if err != nil {
+			return nil, err
+		}
+		return &SchemaObject{Ref: addSchemaRefLinkPrefix(typeID)}, nil
+	}
+
+	baseTypeID, err := p.registerType(pkgPath, pkgName, baseTypeName)
+	if err != nil {
+		return nil, err
+	}
+	overrideSchema := &SchemaObject{
+		Type:       "object",
+		Properties: orderedmap.New(),
+	}
+	for _, override := range overrides {
+		propertySchema, err := p.parseOverrideSchemaObject(pkgPath, pkgName, override[1])
+		if err != nil {
+			return nil, err
+		}
+		overrideSchema.Properties.Set(override[0], propertySchema)
+	}
+	return &SchemaObject{
+		AllOf: []*SchemaObject{
+			{Ref: addSchemaRefLinkPrefix(baseTypeID)},
+			overrideSchema,
+		},
+	}, nil
+}
+
+// resolveTypeArg resolves a type argument where the generic type is instantiated, since the generic type
+// may be declared in a package which does not know it. Named types are registered and replaced by their IDs.
+func (p *parser) resolveTypeArg(pkgPath, pkgName, typeArg string) (string, error) {
+	pkgPath, pkgName, typeArg = p.resolveTypeAlias(pkgPath, pkgName, strings.TrimLeft(typeArg, "*"))
+	if strings.HasPrefix(typeArg, "[]") {
+		itemTypeArg, err := p.resolveTypeArg(pkgPath, pkgName, typeArg[2:])
+		if err != nil {
+			return "", err
+		}
+		return "[]" + itemTypeArg, nil
+	} else if keyTypeName, valueTypeName, ok := parseMapType(typeArg); ok {
+		valueTypeArg, err := p.resolveTypeArg(pkgPath, pkgName, valueTypeName)
+		if err != nil {
+			return "", err
+		}
+		return "map[" + keyTypeName + "]" + valueTypeArg, nil
+	} else if isBasicGoType(typeArg) || isWellKnownType(typeArg) || typeArg == "interface{}" {
+		return typeArg, nil
+	}
+	return p.registerType(pkgPath, pkgName, typeArg)
+}
+
+// parseAnonymousSchemaObject returns the inline schema of a type with an anonymous struct,
+// like struct{...}, []struct{...} or map[string]*struct{...}, or nil for other types.
+func (p *parser) parseAnonymousSchemaObject(pkgPath, pkgName string, astExpr ast.Expr) (*SchemaObject, error) {
+	switch astType := astExpr.(type) {
+	case *ast.StarExpr:
+		return p.parseAnonymousSchemaObject(pkgPath, pkgName, astType.X)
+	case *ast.StructType:
+		schemaObject := &SchemaObject{Type: "object"}
+		if astType.Fields != nil {
+			err := p.parseSchemaPropertiesFromStructFields(pkgPath, pkgName, schemaObject, astType.Fields.List)
 			if err != nil {
-				p.debug("parseSchemaObject parse array items err:", err)
-			} else {
-				propertySchema.Ref = addSchemaRefLinkPrefix(schemaItemsSchemeaObjectID)
+				return nil, err
 			}
-		} else if isGoTypeOASType(typeAsString) {
-			propertySchema.Type = goTypesOASTypes[typeAsString]
 		}
+		return schemaObject, nil
+	case *ast.ArrayType:
+		items, err := p.parseAnonymousSchemaObject(pkgPath, pkgName, astType.Elt)
+		if items == nil || err != nil {
+			return nil, err
+		}
+		return &SchemaObject{Type: "array", Items: items}, nil
+	case *ast.MapType:
+		additionalProperties, err := p.parseAnonymousSchemaObject(pkgPath, pkgName, astType.Value)
+		if additionalProperties == nil || err != nil {
+			return nil, err
+		}
+		schemaObject := &SchemaObject{Type: "object", AdditionalProperties: additionalProperties}
+		if keyTypeAsString := p.getTypeAsString(astType.Key); keyTypeAsString != "string" {
+			schemaObject.GoKeyType = keyTypeAsString
+		}
+		return schemaObject, nil
 	}
+	return nil, nil
+}
 
-	return &schemaObject, nil
+// getTypeMappingSchemaObject returns a copy of the schema which the type is mapped to by --type-mapping,
+// with the ID of the type. The package of the type is resolved from the imports of the package.
+func (p *parser) getTypeMappingSchemaObject(pkgName, typeName string) (*SchemaObject, bool) {
+	if len(p.TypeMappings) == 0 {
+		return nil, false
+	}
+	typePkgName, name := pkgName, typeName
+	if i := strings.LastIndex(typeName, "."); i != -1 {
+		typePkgName, name = typeName[:i], typeName[i+1:]
+		if importedPkgNames := p.PkgNameImportedPkgAlias[pkgName][typePkgName]; len(importedPkgNames) != 0 {
+			typePkgName = importedPkgNames[0]
+		}
+	} else if p.getLocalTypeName(pkgName, typeName) != "" {
+		return nil, false
+	}
+	mappedSchema, ok := p.TypeMappings[typePkgName+"."+name]
+	if !ok {
+		return nil, false
+	}
+	schemaObject := *mappedSchema
+	schemaObject.PkgName = typePkgName
+	schemaObject.ID = genSchemeaObjectID(typePkgName, name)
+	return &schemaObject, true
+}
+
+// getLocalTypeName returns the key of the type declared in the handler func being parsed,
+// like GetUser@Response, or "" when there is no such type.
+func (p *parser) getLocalTypeName(pkgName, typeName string) string {
+	if p.FuncScope == "" {
+		return ""
+	}
+	localTypeName := p.FuncScope + "@" + typeName
+	if _, ok := p.TypeSpecs[pkgName][localTypeName]; !ok {
+		return ""
+	}
+	return localTypeName
+}
+
+// getSchemaObjectID returns the ID of the type in the scope of the handler func being parsed,
+// which is a type declared in the func, like pkg.GetUser.Response, before a type of the package.
+func (p *parser) getSchemaObjectID(pkgName, typeName string) string {
+	if localTypeName := p.getLocalTypeName(pkgName, typeName); localTypeName != "" {
+		scope := strings.Split(localTypeName, "@")
+		return genSchemeaObjectID(strings.Join(append([]string{pkgName}, scope[:len(scope)-1]...), "/"), typeName)
+	}
+	return genSchemeaObjectID(pkgName, typeName)
 }
 
 func (p *parser) getTypeSpec(pkgPath, pkgName, typeName string) (*ast.TypeSpec, bool) {
@@ -1069,9 +1902,66 @@ func (p *parser) getTypeSpec(pkgPath, pkgName, typeName string) (*ast.TypeSpec,
 	return astTypeSpec, true
 }
 
-func (p *parser) parseSchemaPropertiesFromStructFields(pkgPath, pkgName string, structSchema *SchemaObject, astFields []*ast.Field) {
+// getWellKnownTypeName returns the name of a well-known type in wellKnownTypes, like url.URL of neturl.URL,
+// which is found by the import path of its package in the imports of the package.
+func (p *parser) getWellKnownTypeName(pkgName, typeName string) (string, bool) {
+	i := strings.Index(typeName, ".")
+	if i <= 0 {
+		return "", false
+	}
+	for _, importPath := range p.PkgNameImportedPkgAlias[pkgName][typeName[:i]] {
+		if wellKnownPkgName, ok := wellKnownPkgs[importPath]; ok && isWellKnownType(wellKnownPkgName+typeName[i:]) {
+			return wellKnownPkgName + typeName[i:], true
+		}
+	}
+	return "", false
+}
+
+// resolveTypeAlias follows the aliases, like `type Color = string` or `type Member = models.User`,
+// to the type they denote, which is returned with the package where it is written.
+// Other types are returned as they are.
+func (p *parser) resolveTypeAlias(pkgPath, pkgName, typeName string) (string, string, string) {
+	for {
+		if wellKnownTypeName, ok := p.getWellKnownTypeName(pkgName, typeName); ok {
+			return pkgPath, pkgName, wellKnownTypeName
+		}
+		if isBasicGoType(typeName) || isWellKnownType(typeName) || strings.ContainsAny(typeName, "[]{}*() ") {
+			return pkgPath, pkgName, typeName
+		}
+		specPkgPath, specPkgName, specTypeName := pkgPath, pkgName, typeName
+		if typeNameParts := strings.Split(typeName, "."); len(typeNameParts) == 2 {
+			specPkgName, specTypeName = typeNameParts[0], typeNameParts[1]
+			if _, ok := p.TypeSpecs[specPkgName]; !ok && len(p.PkgNameImportedPkgAlias[pkgName][specPkgName]) != 0 {
+				specPkgName = p.PkgNameImportedPkgAlias[pkgName][specPkgName][0]
+			}
+			specPkgPath = ""
+			for i := range p.KnownPkgs {
+				if specPkgName == p.KnownPkgs[i].Name {
+					specPkgPath = p.KnownPkgs[i].Path
+					break
+				}
+			}
+		} else if len(typeNameParts) != 1 {
+			return pkgPath, pkgName, typeName
+		} else if localTypeName := p.getLocalTypeName(pkgName, typeName); localTypeName != "" {
+			specTypeName = localTypeName
+		}
+		typeSpec, ok := p.getTypeSpec(specPkgPath, specPkgName, specTypeName)
+		if !ok || !typeSpec.Assign.IsValid() || typeSpec.TypeParams != nil {
+			return pkgPath, pkgName, typeName
+		}
+		// an alias of an anonymous struct is parsed as a named type
+		aliasedTypeName := strings.TrimLeft(p.getTypeAsString(typeSpec.Type), "*")
+		if strings.Contains(aliasedTypeName, "struct{}") {
+			return pkgPath, pkgName, typeName
+		}
+		pkgPath, pkgName, typeName = specPkgPath, specPkgName, aliasedTypeName
+	}
+}
+
+func (p *parser) parseSchemaPropertiesFromStructFields(pkgPath, pkgName string, structSchema *SchemaObject, astFields []*ast.Field) error {
 	if astFields == nil {
-		return
+		return nil
 	}
 	var err error
 	structSchema.Properties = orderedmap.New()
@@ -1085,35 +1975,42 @@ astFieldsLoop:
 		}
 		fieldSchema := &SchemaObject{}
 		typeAsString := p.getTypeAsString(astField.Type)
-		typeAsString = strings.TrimLeft(typeAsString, "*")
-		if strings.HasPrefix(typeAsString, "[]") {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+		// the package of the type changes with an alias of a type of another package
+		fieldPkgPath, fieldPkgName, typeAsString := p.resolveTypeAlias(pkgPath, pkgName, strings.TrimLeft(typeAsString, "*"))
+		overrideSchema, err := p.parseTypeOverrideTag(pkgPath, pkgName, astField)
+		if err != nil {
+			return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse type of %s: %s", astField.Names[0].Name, err)
+		}
+		if overrideSchema != nil {
+			fieldSchema = overrideSchema
+		} else if anonymousSchema, err := p.parseAnonymousSchemaObject(pkgPath, pkgName, astField.Type); err != nil {
+			return err
+		} else if anonymousSchema != nil {
+			fieldSchema = anonymousSchema
+		} else if strings.HasPrefix(typeAsString, "[]") {
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse type of %s: %s", astField.Names[0].Name, err)
 			}
-		} else if strings.HasPrefix(typeAsString, "map[]") {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+		} else if strings.HasPrefix(typeAsString, "map[") {
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse type of %s: %s", astField.Names[0].Name, err)
 			}
-		} else if typeAsString == "time.Time" {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+		} else if isWellKnownType(typeAsString) {
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse type of %s: %s", astField.Names[0].Name, err)
 			}
 		} else if strings.HasPrefix(typeAsString, "interface{}") {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse type of %s: %s", astField.Names[0].Name, err)
 			}
 		} else if !isBasicGoType(typeAsString) {
-			fieldSchemaSchemeaObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+			fieldSchemaSchemeaObjectID, err := p.registerType(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug("parseSchemaPropertiesFromStructFields err:", err)
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse type of %s: %s", astField.Names[0].Name, err)
 			} else {
 				fieldSchema.ID = fieldSchemaSchemeaObjectID
 				schema, ok := p.KnownIDSchema[fieldSchemaSchemeaObjectID]
@@ -1129,6 +2026,9 @@ astFieldsLoop:
 			fieldSchema.Type = goTypesOASTypes[typeAsString]
 		}
 
+		// a pointer may be nil
+		_, nullable := astField.Type.(*ast.StarExpr)
+
 		name := astField.Names[0].Name
 		fieldSchema.FieldName = name
 		_, disabled := structSchema.DisabledFieldNames[name]
@@ -1136,8 +2036,15 @@ astFieldsLoop:
 			continue
 		}
 
+		if _, deprecated := getDeprecatedReason(astField.Doc); deprecated {
+			fieldSchema.Deprecated = true
+		} else if _, deprecated := getDeprecatedReason(astField.Comment); deprecated {
+			fieldSchema.Deprecated = true
+		}
+
 		if astField.Tag != nil {
-			astFieldTag := reflect.StructTag(strings.Trim(astField.Tag.Value, "`"))
+			fieldSchema.FieldTag = strings.Trim(astField.Tag.Value, "`")
+			astFieldTag := reflect.StructTag(fieldSchema.FieldTag)
 			tagText := ""
 
 			if tag := astFieldTag.Get("goas"); tag != "" {
@@ -1149,8 +2056,14 @@ astFieldsLoop:
 					structSchema.DisabledFieldNames[name] = struct{}{}
 					fieldSchema.Deprecated = true
 					continue astFieldsLoop
+				} else if v == "nullable" {
+					nullable = true
 				}
 			}
+			err = p.parsePolymorphicTag(pkgPath, pkgName, fieldSchema, tagValues)
+			if err != nil {
+				p.debugf("parseSchemaPropertiesFromStructFields cannot parse goas tag of %s: %s", name, err)
+			}
 
 			if tag := astFieldTag.Get("json"); tag != "" {
 				tagText = tag
@@ -1170,41 +2083,10 @@ astFieldsLoop:
 			}
 
 			if tag := astFieldTag.Get("example"); tag != "" {
-				switch fieldSchema.Type {
-				case "boolean":
-					fieldSchema.Example, _ = strconv.ParseBool(tag)
-				case "integer":
-					fieldSchema.Example, _ = strconv.Atoi(tag)
-				case "number":
-					fieldSchema.Example, _ = strconv.ParseFloat(tag, 64)
-				case "array":
-					b, err := json.RawMessage(tag).MarshalJSON()
-					if err != nil {
-						fieldSchema.Example = "invalid example"
-					} else {
-						sliceOfInterface := []interface{}{}
-						err := json.Unmarshal(b, &sliceOfInterface)
-						if err != nil {
-							fieldSchema.Example = "invalid example"
-						} else {
-							fieldSchema.Example = sliceOfInterface
-						}
-					}
-				case "object":
-					b, err := json.RawMessage(tag).MarshalJSON()
-					if err != nil {
-						fieldSchema.Example = "invalid example"
-					} else {
-						mapOfInterface := map[string]interface{}{}
-						err := json.Unmarshal(b, &mapOfInterface)
-						if err != nil {
-							fieldSchema.Example = "invalid example"
-						} else {
-							fieldSchema.Example = mapOfInterface
-						}
-					}
-				default:
-					fieldSchema.Example = tag
+				fieldSchema.Example, err = parseValueByType(fieldSchema.Type, tag)
+				// an invalid bool or number is the zero value
+				if err != nil && (fieldSchema.Type == "array" || fieldSchema.Type == "object") {
+					fieldSchema.Example = "invalid example"
 				}
 
 				if fieldSchema.Example != nil && len(fieldSchema.Ref) != 0 {
@@ -1212,6 +2094,48 @@ astFieldsLoop:
 				}
 			}
 
+			if tag := astFieldTag.Get("const"); tag != "" {
+				value, err := parseValueByType(fieldSchema.Type, tag)
+				if err != nil {
+					p.debugf("parseSchemaPropertiesFromStructFields cannot parse const %s of %s: %s", tag, name, err)
+				} else {
+					fieldSchema.Enum = []interface{}{value}
+					wrapRef(fieldSchema)
+				}
+			}
+
+			if tag := astFieldTag.Get("enum"); tag != "" {
+				enumSchema := fieldSchema
+				if fieldSchema.Type == "array" && fieldSchema.Items != nil {
+					// items may be shared with the schema of a named type
+					itemsSchema := *fieldSchema.Items
+					fieldSchema.Items = &itemsSchema
+					enumSchema = fieldSchema.Items
+				}
+				enums := []interface{}{}
+				for _, v := range strings.Split(tag, ",") {
+					value, err := parseValueByType(enumSchema.Type, strings.TrimSpace(v))
+					if err != nil {
+						p.debugf("parseSchemaPropertiesFromStructFields cannot parse enum %s of %s: %s", v, name, err)
+						continue
+					}
+					enums = append(enums, value)
+				}
+				enumSchema.Enum = enums
+				enumSchema.EnumVarNames = nil
+				wrapRef(fieldSchema)
+			}
+
+			if tag := astFieldTag.Get("validate"); tag != "" {
+				required, changed := p.parseValidateTag(fieldSchema, tag)
+				if required {
+					isRequired = true
+				}
+				if changed {
+					wrapRef(fieldSchema)
+				}
+			}
+
 			if _, ok := astFieldTag.Lookup("required"); ok || isRequired {
 				structSchema.Required = append(structSchema.Required, name)
 			}
@@ -1221,43 +2145,44 @@ astFieldsLoop:
 			}
 		}
 
+		if nullable {
+			setNullable(fieldSchema)
+		}
+
 		structSchema.Properties.Set(name, fieldSchema)
 	}
+	var allOf []*SchemaObject
 	for _, astField := range astFields {
 		if len(astField.Names) > 0 {
 			continue
 		}
 		fieldSchema := &SchemaObject{}
 		typeAsString := p.getTypeAsString(astField.Type)
-		typeAsString = strings.TrimLeft(typeAsString, "*")
+		fieldPkgPath, fieldPkgName, typeAsString := p.resolveTypeAlias(pkgPath, pkgName, strings.TrimLeft(typeAsString, "*"))
 		if strings.HasPrefix(typeAsString, "[]") {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse embedded type %s: %s", typeAsString, err)
 			}
-		} else if strings.HasPrefix(typeAsString, "map[]") {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+		} else if strings.HasPrefix(typeAsString, "map[") {
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse embedded type %s: %s", typeAsString, err)
 			}
-		} else if typeAsString == "time.Time" {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+		} else if isWellKnownType(typeAsString) {
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse embedded type %s: %s", typeAsString, err)
 			}
 		} else if strings.HasPrefix(typeAsString, "interface{}") {
-			fieldSchema, err = p.parseSchemaObject(pkgPath, pkgName, typeAsString)
+			fieldSchema, err = p.parseSchemaObject(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug(err)
-				return
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse embedded type %s: %s", typeAsString, err)
 			}
 		} else if !isBasicGoType(typeAsString) {
-			fieldSchemaSchemeaObjectID, err := p.registerType(pkgPath, pkgName, typeAsString)
+			fieldSchemaSchemeaObjectID, err := p.registerType(fieldPkgPath, fieldPkgName, typeAsString)
 			if err != nil {
-				p.debug("parseSchemaPropertiesFromStructFields err:", err)
+				return fmt.Errorf("parseSchemaPropertiesFromStructFields: cannot parse embedded type %s: %s", typeAsString, err)
 			} else {
 				fieldSchema.ID = fieldSchemaSchemeaObjectID
 				schema, ok := p.KnownIDSchema[fieldSchemaSchemeaObjectID]
@@ -1282,10 +2207,16 @@ astFieldsLoop:
 					}
 					propertySchema, _ := fieldSchema.Properties.Get(propertyName)
 					structSchema.Properties.Set(propertyName, propertySchema)
+					if isInStringList(fieldSchema.Required, propertyName) {
+						structSchema.Required = append(structSchema.Required, propertyName)
+					}
 				}
 			} else if len(fieldSchema.Ref) != 0 && len(fieldSchema.ID) != 0 {
 				refSchema, ok := p.KnownIDSchema[fieldSchema.ID]
-				if ok {
+				if ok && (isEmbeddedAllOf(astField) || refSchema.AllOf != nil) {
+					// a composed type can not be flattened, so it is composed too
+					allOf = append(allOf, &SchemaObject{Ref: fieldSchema.Ref})
+				} else if ok && refSchema.Properties != nil {
 					for _, propertyName := range refSchema.Properties.Keys() {
 						refPropertySchema, _ := refSchema.Properties.Get(propertyName)
 						_, disabled := structSchema.DisabledFieldNames[refPropertySchema.(*SchemaObject).FieldName]
@@ -1299,12 +2230,68 @@ astFieldsLoop:
 						}
 
 						structSchema.Properties.Set(propertyName, refPropertySchema)
+						if isInStringList(refSchema.Required, propertyName) {
+							structSchema.Required = append(structSchema.Required, propertyName)
+						}
 					}
 				}
 			}
 			continue
 		}
 	}
+
+	if len(allOf) != 0 {
+		// the fields of the struct are the last part of the composition
+		if len(structSchema.Properties.Keys()) != 0 {
+			allOf = append(allOf, &SchemaObject{
+				Type:       "object",
+				Required:   structSchema.Required,
+				Properties: structSchema.Properties,
+			})
+		}
+		structSchema.AllOf = allOf
+		structSchema.Type = ""
+		structSchema.Required = nil
+		structSchema.Properties = nil
+	}
+	return nil
+}
+
+// isEmbeddedAllOf reports whether the embedded field is tagged with goas:"allOf",
+// which composes the struct with the embedded type instead of copying its fields.
+func isEmbeddedAllOf(astField *ast.Field) bool {
+	if astField.Tag == nil {
+		return false
+	}
+	astFieldTag := reflect.StructTag(strings.Trim(astField.Tag.Value, "`"))
+	return isInStringList(strings.Split(astFieldTag.Get("goas"), ","), "allOf")
+}
+
+// parseValueByType parses the value of a struct tag, like example, according to the schema type.
+func parseValueByType(schemaType, value string) (interface{}, error) {
+	switch schemaType {
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "integer":
+		return strconv.Atoi(value)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "array":
+		sliceOfInterface := []interface{}{}
+		err := json.Unmarshal([]byte(value), &sliceOfInterface)
+		if err != nil {
+			return nil, err
+		}
+		return sliceOfInterface, nil
+	case "object":
+		mapOfInterface := map[string]interface{}{}
+		err := json.Unmarshal([]byte(value), &mapOfInterface)
+		if err != nil {
+			return nil, err
+		}
+		return mapOfInterface, nil
+	}
+	return value, nil
 }
 
 func (p *parser) getTypeAsString(fieldType interface{}) string {
@@ -1315,7 +2302,7 @@ func (p *parser) getTypeAsString(fieldType interface{}) string {
 
 	astMapType, ok := fieldType.(*ast.MapType)
 	if ok {
-		return fmt.Sprintf("map[]%v", p.getTypeAsString(astMapType.Value))
+		return fmt.Sprintf("map[%v]%v", p.getTypeAsString(astMapType.Key), p.getTypeAsString(astMapType.Value))
 	}
 
 	_, ok = fieldType.(*ast.InterfaceType)
@@ -1323,6 +2310,37 @@ func (p *parser) getTypeAsString(fieldType interface{}) string {
 		return "interface{}"
 	}
 
+	_, ok = fieldType.(*ast.StructType)
+	if ok {
+		return "struct{}"
+	}
+
+	astIndexExpr, ok := fieldType.(*ast.IndexExpr)
+	if ok {
+		return fmt.Sprintf("%v[%v]", p.getTypeAsString(astIndexExpr.X), p.getTypeAsString(astIndexExpr.Index))
+	}
+
+	astIndexListExpr, ok := fieldType.(*ast.IndexListExpr)
+	if ok {
+		typeArgs := []string{}
+		for _, astIndex := range astIndexListExpr.Indices {
+			typeArgs = append(typeArgs, p.getTypeAsString(astIndex))
+		}
+		return fmt.Sprintf("%v[%v]", p.getTypeAsString(astIndexListExpr.X), strings.Join(typeArgs, ","))
+	}
+
+	// type parameter of the generic type being parsed
+	if astIdent, ok := fieldType.(*ast.Ident); ok {
+		if typeArg, ok := p.TypeArgs[astIdent.Name]; ok {
+			return typeArg
+		}
+	}
+
+	astIdent, ok := fieldType.(*ast.Ident)
+	if ok && astIdent.Name == "any" {
+		return "interface{}"
+	}
+
 	astStarExpr, ok := fieldType.(*ast.StarExpr)
 	if ok {
 		// return fmt.Sprintf("*%v", p.getTypeAsString(astStarExpr.X))
@@ -1349,3 +2367,7 @@ func (p *parser) debugf(format string, args ...interface{}) {
 		log.Printf(format, args...)
 	}
 }
+
+func (p *parser) warnf(format string, args ...interface{}) {
+	log.Printf("Warning: "+format, args...)
+}
@@ -0,0 +1,937 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	goparser "go/parser"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestParser parses the module in testdata/dir.
+func newTestParser(t *testing.T, dir, openAPIVersion, typeMappingPath, marshalerFallback string) *parser {
+	t.Helper()
+	p, err := newParser(filepath.Join("testdata", dir), "", "", openAPIVersion, typeMappingPath, marshalerFallback, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// assertJSON checks that v is marshaled into the same JSON value as want.
+func assertJSON(t *testing.T, name string, v interface{}, want string) {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, wantValue interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("%s: invalid JSON %s: %s", name, want, err)
+	}
+	if !reflect.DeepEqual(got, wantValue) {
+		t.Errorf("%s: got %s, want %s", name, b, want)
+	}
+}
+
+func TestCreateOASFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		output string
+		format string
+		prefix string
+	}{
+		{"oas.json", "", "{"},
+		{"oas.yaml", "", "openapi: 3.0.0"},
+		{"oas.yml", "", "openapi: 3.0.0"},
+		{"oas.out", "yaml", "openapi: 3.0.0"},
+		{"oas.yaml", "JSON", "{"},
+	}
+	for _, tt := range tests {
+		p, err := newParser(filepath.Join("testdata", "basic"), "", "", "", "", "", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, tt.output)
+		err = p.CreateOASFile(path, tt.format)
+		if err != nil {
+			t.Fatalf("CreateOASFile(%s, %s): %s", tt.output, tt.format, err)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(b), tt.prefix) {
+			t.Errorf("CreateOASFile(%s, %s) wrote %.40q, want prefix %q", tt.output, tt.format, b, tt.prefix)
+		}
+		var document OpenAPIObject
+		err = unmarshalYaml(b, &document)
+		if err != nil {
+			t.Errorf("CreateOASFile(%s, %s) wrote invalid document: %s", tt.output, tt.format, err)
+		} else if document.Paths["/users"] == nil || document.Paths["/users"].Get == nil {
+			t.Errorf("CreateOASFile(%s, %s) wrote no /users operation", tt.output, tt.format)
+		}
+	}
+
+	p, err := newParser(filepath.Join("testdata", "basic"), "", "", "", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.CreateOASFile(filepath.Join(dir, "oas.xml"), "xml")
+	if err == nil {
+		t.Errorf("CreateOASFile with xml format returned no error")
+	}
+}
+
+func TestParseWebhook(t *testing.T) {
+	p := newTestParser(t, "webhooks", "3.1", "", "")
+	p.convertToOpenAPI31()
+	assertJSON(t, "webhooks", p.OpenAPI.Webhooks, `{
+		"userCreated": {
+			"post": {
+				"summary": "User created.",
+				"operationId": "webhooks_UserCreated",
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/example.com.webhooks.User"}}}
+				},
+				"responses": {"204": {"description": "Received."}}
+			}
+		}
+	}`)
+	assertJSON(t, "components", p.OpenAPI.Components.Schemas, `{
+		"example.com.webhooks.User": {
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"email": {"type": ["string", "null"], "examples": ["bob@example.com"]}
+			}
+		}
+	}`)
+	if len(p.OpenAPI.Paths) != 0 {
+		t.Errorf("webhook is added to paths")
+	}
+
+	p, err := newParser(filepath.Join("testdata", "webhooks"), "", "", "3.0", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.parse(); err == nil {
+		t.Errorf("webhook of OpenAPI 3.0 returned no error")
+	}
+}
+
+func TestParseSecurity(t *testing.T) {
+	p := newTestParser(t, "security", "", "", "")
+	assertJSON(t, "security", p.OpenAPI.Security, `[{"Bearer": []}]`)
+	assertJSON(t, "securitySchemes", p.OpenAPI.Components.SecuritySchemes, `{
+		"Bearer": {"type": "http", "scheme": "bearer", "description": "Input your token"},
+		"ApiKey": {"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+		"OAuth": {
+			"type": "oauth2",
+			"flows": {
+				"authorizationCode": {
+					"authorizationUrl": "/oauth/authorize",
+					"tokenUrl": "/oauth/token",
+					"scopes": {"read": "Read access", "write": "Write access"}
+				}
+			}
+		}
+	}`)
+
+	tests := []struct {
+		path     string
+		security string
+	}{
+		{"/inherited", `null`},
+		{"/scoped", `[{"OAuth": ["read", "write"]}]`},
+		{"/either", `[{"ApiKey": []}, {"Bearer": [], "OAuth": ["read"]}]`},
+		{"/public", `[]`},
+	}
+	for _, tt := range tests {
+		assertJSON(t, tt.path, p.OpenAPI.Paths[tt.path].Get.Security, tt.security)
+	}
+}
+
+func TestParseSecurityComment(t *testing.T) {
+	p := &parser{}
+	p.OpenAPI.Components.SecuritySchemes = map[string]*SecuritySchemeObject{
+		"Bearer": {Type: "http", Scheme: "bearer"},
+		"OAuth":  {Type: "oauth2"},
+	}
+	tests := []struct {
+		comment  string
+		security []map[string][]string
+		wantErr  bool
+	}{
+		{"Bearer", []map[string][]string{{"Bearer": {}}}, false},
+		{"OAuth read write", []map[string][]string{{"OAuth": {"read", "write"}}}, false},
+		{"Bearer && OAuth read", []map[string][]string{{"Bearer": {}, "OAuth": {"read"}}}, false},
+		{"Unknown", nil, true},
+		{"Bearer && Unknown", nil, true},
+		{"&&", nil, true},
+	}
+	for _, tt := range tests {
+		operation := &OperationObject{}
+		err := p.parseSecurityComment(operation, tt.comment)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSecurityComment(%q) error = %v, want error %v", tt.comment, err, tt.wantErr)
+		}
+		if tt.wantErr {
+			continue
+		}
+		if operation.Security == nil || !reflect.DeepEqual(*operation.Security, tt.security) {
+			t.Errorf("parseSecurityComment(%q) = %v, want %v", tt.comment, operation.Security, tt.security)
+		}
+	}
+}
+
+func TestParseOperationID(t *testing.T) {
+	p := newTestParser(t, "operationids", "", "", "")
+	tests := []struct {
+		operation   *OperationObject
+		operationID string
+	}{
+		{p.OpenAPI.Paths["/users"].Get, "users_List"},
+		{p.OpenAPI.Paths["/users/{id}"].Get, "users_Handler_Get"},
+		{p.OpenAPI.Paths["/users/{id}"].Delete, "deleteUser"},
+		{p.OpenAPI.Paths["/groups"].Get, "groups_List"},
+	}
+	for _, tt := range tests {
+		if tt.operation.OperationID != tt.operationID {
+			t.Errorf("operationId of %s = %s, want %s", tt.operation.Summary, tt.operation.OperationID, tt.operationID)
+		}
+	}
+
+	p, err := newParser(filepath.Join("testdata", "operationids_duplicate"), "", "", "", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.parse()
+	if err == nil || !strings.Contains(err.Error(), "duplicate operationId list") {
+		t.Errorf("duplicate operationId returned error %v", err)
+	}
+}
+
+func TestParseDeprecated(t *testing.T) {
+	p := newTestParser(t, "deprecated", "", "", "")
+	tests := []struct {
+		operation   *OperationObject
+		description string
+		sunset      string
+	}{
+		{p.OpenAPI.Paths["/users"].Get, "Deprecated: use ListUsers2, which is paged.", ""},
+		{p.OpenAPI.Paths["/old-users"].Get, "Lists the old users. Deprecated: use ListUsers2", "2027-01-31"},
+		{p.OpenAPI.Paths["/users2"].Get, "", ""},
+	}
+	for _, tt := range tests {
+		if !tt.operation.Deprecated {
+			t.Errorf("%s is not deprecated", tt.operation.Summary)
+		}
+		if tt.operation.Description != tt.description {
+			t.Errorf("description of %s = %q, want %q", tt.operation.Summary, tt.operation.Description, tt.description)
+		}
+		if tt.operation.Sunset != tt.sunset {
+			t.Errorf("sunset of %s = %q, want %q", tt.operation.Summary, tt.operation.Sunset, tt.sunset)
+		}
+	}
+	assertJSON(t, "User", p.OpenAPI.Components.Schemas["example.com.deprecated.User"], `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"full_name": {"type": "string", "deprecated": true},
+			"nick": {"type": "string", "deprecated": true}
+		}
+	}`)
+}
+
+func TestParseHeader(t *testing.T) {
+	p := newTestParser(t, "headers", "", "", "")
+	assertJSON(t, "responses", p.OpenAPI.Paths["/user"].Get.Responses, `{
+		"200": {
+			"description": "The user.",
+			"headers": {
+				"X-Rate-Limit": {"description": "Requests per hour.", "schema": {"type": "integer", "format": "int64"}},
+				"X-Expires": {"schema": {"type": "string", "format": "date-time"}},
+				"X-Tags": {"description": "Tags.", "schema": {"type": "array", "items": {"type": "string"}}}
+			},
+			"content": {"application/json": {"schema": {"$ref": "#/components/schemas/example.com.headers.User"}}}
+		},
+		"429": {
+			"description": "Too Many Requests",
+			"headers": {
+				"Retry-After": {"description": "Seconds to wait.", "schema": {"type": "integer", "format": "int64"}}
+			}
+		}
+	}`)
+}
+
+func TestParseHeaderComment(t *testing.T) {
+	tests := []string{
+		"",
+		"200",
+		"ok X-Rate-Limit int",
+	}
+	for _, comment := range tests {
+		p := &parser{}
+		err := p.parseHeaderComment("", "", &OperationObject{Responses: ResponsesObject{}}, comment)
+		if err == nil {
+			t.Errorf("parseHeaderComment(%q) returned no error", comment)
+		}
+	}
+}
+
+func TestParseMediaTypeComments(t *testing.T) {
+	p := newTestParser(t, "media", "", "", "")
+	userRef := `{"$ref": "#/components/schemas/example.com.media.User"}`
+	assertJSON(t, "PutUser", p.OpenAPI.Paths["/user"].Put, `{
+		"summary": "Put user.",
+		"operationId": "media_PutUser",
+		"requestBody": {
+			"required": true,
+			"content": {"application/json": {"schema": `+userRef+`}, "application/xml": {"schema": `+userRef+`}}
+		},
+		"responses": {
+			"200": {"description": "The user.", "content": {"application/json": {"schema": `+userRef+`}}},
+			"201": {"description": "The created user.", "content": {"application/xml": {"schema": `+userRef+`}, "text/x-yaml": {"schema": `+userRef+`}}},
+			"400": {"description": "The error.", "content": {"text/plain": {"schema": {"type": "string"}}}},
+			"500": {"description": "Internal Server Error", "content": {"application/json": {"schema": {"type": "string"}}}}
+		}
+	}`)
+	assertJSON(t, "Login", p.OpenAPI.Paths["/login"].Post, `{
+		"summary": "Login.",
+		"operationId": "media_Login",
+		"requestBody": {
+			"required": true,
+			"content": {
+				"application/x-www-form-urlencoded": {
+					"schema": {"type": "object", "properties": {"name": {"type": "string", "format": "string", "description": "The name."}}}
+				}
+			}
+		},
+		"responses": {
+			"200": {"description": "The token.", "content": {"application/octet-stream": {"schema": {"type": "string", "format": "binary"}}}}
+		}
+	}`)
+
+	p, err := newParser(filepath.Join("testdata", "media_unknown"), "", "", "", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.parse()
+	if err == nil || !strings.Contains(err.Error(), "unknown media type jsno") {
+		t.Errorf("unknown media type returned error %v", err)
+	}
+}
+
+func TestParseResponses(t *testing.T) {
+	p := newTestParser(t, "responses", "", "", "")
+	assertJSON(t, "DeleteUser", p.OpenAPI.Paths["/users/{id}"].Delete.Responses, `{
+		"204": {"description": "No Content"},
+		"404": {"description": "No such user."},
+		"default": {
+			"description": "Default response",
+			"content": {"application/json": {"schema": {"$ref": "#/components/schemas/example.com.responses.Error"}}}
+		}
+	}`)
+	assertJSON(t, "Ping", p.OpenAPI.Paths["/ping"].Get.Responses, `{
+		"299": {"description": "Response"},
+		"default": {"description": "Default response"}
+	}`)
+}
+
+func TestParseResponseComment(t *testing.T) {
+	tests := []string{
+		"",
+		"ok",
+		"ok object string",
+		"200 string string",
+	}
+	for _, comment := range tests {
+		p := &parser{}
+		err := p.parseResponseComment("", "", &OperationObject{Responses: ResponsesObject{}}, comment)
+		if err == nil {
+			t.Errorf("parseResponseComment(%q) returned no error", comment)
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	p := newTestParser(t, "params", "", "", "")
+	assertJSON(t, "parameters", p.OpenAPI.Paths["/users/{id}"].Get.Parameters, `[
+		{
+			"name": "ids", "in": "query", "description": "Ids of users.",
+			"schema": {"type": "array", "items": {"type": "integer", "format": "int64"}},
+			"style": "form", "explode": false
+		},
+		{
+			"name": "sort", "in": "query", "description": "Sort order.",
+			"schema": {"type": "string", "format": "string", "description": "Sort order.", "enum": ["asc", "desc"]}
+		},
+		{
+			"name": "since", "in": "query", "description": "Since.",
+			"schema": {"type": "string", "format": "date-time"}
+		},
+		{
+			"name": "status", "in": "query", "description": "Status.",
+			"schema": {"type": "string", "enum": ["active", "banned"], "x-enum-varnames": ["StatusActive", "StatusBanned"]}
+		},
+		{
+			"name": "statuses", "in": "query", "description": "Statuses.",
+			"schema": {"type": "array", "items": {"type": "string", "enum": ["active", "banned"], "x-enum-varnames": ["StatusActive", "StatusBanned"]}},
+			"style": "pipeDelimited"
+		},
+		{
+			"name": "lvl", "in": "query", "description": "Level.",
+			"schema": {"type": "integer", "enum": [1, 2]}
+		},
+		{
+			"name": "lvls", "in": "query", "description": "Levels.",
+			"schema": {"type": "array", "items": {"type": "integer", "enum": [2]}}
+		},
+		{
+			"name": "q", "in": "query", "description": "Query.",
+			"schema": {"type": "string", "format": "string", "description": "Query."},
+			"allowReserved": true
+		},
+		{
+			"name": "X-Request-ID", "in": "header", "description": "Request id.", "required": true,
+			"schema": {"type": "string", "format": "string", "description": "Request id."}
+		},
+		{
+			"name": "id", "in": "path", "description": "Id.", "required": true,
+			"schema": {"type": "integer", "format": "int64", "description": "Id."}
+		}
+	]`)
+	// the enum option does not change the named type
+	assertJSON(t, "Level", p.OpenAPI.Components.Schemas["example.com.params.models.Level"], `{
+		"type": "integer",
+		"enum": [0, 1, 2],
+		"x-enum-varnames": ["LevelLow", "LevelMid", "LevelHigh"]
+	}`)
+}
+
+func TestParseParamOptions(t *testing.T) {
+	tests := []struct {
+		schema  SchemaObject
+		options string
+		want    string
+		wantErr bool
+	}{
+		{SchemaObject{Type: "string"}, "", `{"name": "p", "in": "query", "schema": {"type": "string"}}`, false},
+		{SchemaObject{Type: "string"}, "style=deepObject explode=true allowReserved=false", `{"name": "p", "in": "query", "schema": {"type": "string"}, "style": "deepObject", "explode": true}`, false},
+		{SchemaObject{Type: "integer", Enum: []interface{}{0, 1}, EnumVarNames: []string{"A", "B"}}, "enum=1", `{"name": "p", "in": "query", "schema": {"type": "integer", "enum": [1]}}`, false},
+		{SchemaObject{Type: "array", Items: &SchemaObject{Type: "boolean"}}, "enum=true", `{"name": "p", "in": "query", "schema": {"type": "array", "items": {"type": "boolean", "enum": [true]}}}`, false},
+		{SchemaObject{Type: "string"}, "style=grid", "", true},
+		{SchemaObject{Type: "string"}, "explode=yes", "", true},
+		{SchemaObject{Type: "string"}, "allowReserved=1x", "", true},
+		{SchemaObject{Type: "integer"}, "enum=a,b", "", true},
+		{SchemaObject{Type: "string"}, "required", "", true},
+		{SchemaObject{Type: "string"}, "min=1", "", true},
+	}
+	for _, tt := range tests {
+		p := &parser{}
+		schema := tt.schema
+		parameterObject := &ParameterObject{Name: "p", In: "query", Schema: &schema}
+		err := p.parseParamOptions(parameterObject, tt.options)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseParamOptions(%q) error = %v, want error %v", tt.options, err, tt.wantErr)
+		}
+		if !tt.wantErr {
+			assertJSON(t, tt.options, parameterObject, tt.want)
+		}
+	}
+}
+
+func TestParseParamStruct(t *testing.T) {
+	p := newTestParser(t, "paramstruct", "", "", "")
+	tagged := `
+		{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}},
+		{"name": "page", "in": "query", "example": 1, "schema": {"type": "integer"}},
+		{"name": "sort", "in": "query", "required": true, "schema": {"type": "string"}},
+		{"name": "X-Token", "in": "header", "description": "The token.", "schema": {"type": "string"}},
+		{"name": "session", "in": "cookie", "schema": {"type": "string"}}`
+	// @Params ignores the fields without tags of locations
+	assertJSON(t, "@Params", p.OpenAPI.Paths["/users/{id}"].Get.Parameters, `[`+tagged+`]`)
+	// @Param . puts them in its location, and the others where their tags say
+	assertJSON(t, "@Param .", p.OpenAPI.Paths["/groups/{id}"].Get.Parameters, `[`+tagged+`,
+		{"name": "q", "in": "query", "schema": {"type": "string"}}
+	]`)
+}
+
+func TestParseEnums(t *testing.T) {
+	p := newTestParser(t, "enums", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	// _ is skipped, empty specs repeat the previous expression and PriorityMax is not a new value
+	assertJSON(t, "Priority", schemas["example.com.enums.models.Priority"], `{
+		"type": "integer", "enum": [1, 2], "x-enum-varnames": ["PriorityLow", "PriorityHigh"]
+	}`)
+	assertJSON(t, "Flag", schemas["example.com.enums.models.Flag"], `{
+		"type": "integer", "enum": [1, 2, 4], "x-enum-varnames": ["FlagRead", "FlagWrite", "FlagExec"]
+	}`)
+	// the files are read in the order of their names, and the untyped constants are skipped
+	assertJSON(t, "Status", schemas["example.com.enums.models.Status"], `{
+		"type": "string", "enum": ["closed", "other", "open"], "x-enum-varnames": ["StatusClosed", "StatusOther", "StatusOpen"]
+	}`)
+	assertJSON(t, "Task", schemas["example.com.enums.models.Task"].Properties, `{
+		"priority": {"type": "integer", "$ref": "#/components/schemas/example.com.enums.models.Priority"},
+		"flag": {"type": "integer", "$ref": "#/components/schemas/example.com.enums.models.Flag"},
+		"status": {"type": "string", "$ref": "#/components/schemas/example.com.enums.models.Status"},
+		"kind": {"type": "string", "enum": ["task"]},
+		"size": {"type": "integer", "enum": [1, 2]},
+		"colors": {"type": "array", "items": {"type": "string", "enum": ["red", "blue"]}}
+	}`)
+}
+
+func TestParseValidateTags(t *testing.T) {
+	p := newTestParser(t, "validate", "", "", "")
+	user := p.OpenAPI.Components.Schemas["example.com.validate.User"]
+	assertJSON(t, "required", user.Required, `["name"]`)
+	// datetime=15:04 is not a format, and the alternatives of email|uuid are ignored
+	assertJSON(t, "properties", user.Properties, `{
+		"name": {"type": "string", "minLength": 1, "maxLength": 64},
+		"email": {"type": "string", "format": "email"},
+		"birthday": {"type": "string", "format": "date"},
+		"alarm": {"type": "string"},
+		"age": {"type": "integer", "minimum": 0, "maximum": 150, "exclusiveMaximum": true},
+		"role": {"type": "string", "enum": ["admin", "member"], "allOf": [{"$ref": "#/components/schemas/example.com.validate.Role"}]},
+		"tags": {
+			"type": "array", "maxItems": 3, "uniqueItems": true,
+			"items": {"type": "string", "maxLength": 16, "pattern": "^[a-zA-Z0-9]+$"}
+		},
+		"contact": {"type": "string"}
+	}`)
+	assertJSON(t, "Role", p.OpenAPI.Components.Schemas["example.com.validate.Role"], `{"type": "string"}`)
+}
+
+func TestParseAliases(t *testing.T) {
+	p := newTestParser(t, "aliases", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	for _, id := range []string{"example.com.aliases.Color", "example.com.aliases.Member", "example.com.aliases.Members",
+		"example.com.aliases.Timestamp", "example.com.aliases.models.Tier", "example.com.aliases.models.Base"} {
+		if _, ok := schemas[id]; ok {
+			t.Errorf("alias %s is a component", id)
+		}
+	}
+	// an alias of an anonymous struct is a component like a named type
+	assertJSON(t, "Point", schemas["example.com.aliases.Point"], `{"type": "object", "properties": {"x": {"type": "integer"}}}`)
+	assertJSON(t, "Team", schemas["example.com.aliases.Team"].Properties, `{
+		"color": {"type": "string"},
+		"tier": {"type": "integer", "$ref": "#/components/schemas/example.com.aliases.models.Level"},
+		"leader": {"type": "object", "nullable": true, "allOf": [{"$ref": "#/components/schemas/example.com.aliases.models.User"}]},
+		"members": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}},
+		"scores": {"type": "object", "additionalProperties": {"type": "string"}},
+		"created": {"type": "string", "format": "date-time"},
+		"origin": {"type": "object", "$ref": "#/components/schemas/example.com.aliases.Point"},
+		"id": {"type": "integer"}
+	}`)
+	operation := p.OpenAPI.Paths["/teams/{id}"].Get
+	assertJSON(t, "parameters", operation.Parameters, `[
+		{"name": "color", "in": "query", "description": "Color.", "schema": {"type": "string", "format": "string", "description": "Color."}}
+	]`)
+	assertJSON(t, "header", operation.Responses["200"].Headers["X-Color"].Schema, `{"type": "string", "format": "string"}`)
+	assertJSON(t, "201", operation.Responses["201"].Content["application/json"].Schema, `{"$ref": "#/components/schemas/example.com.aliases.models.User"}`)
+}
+
+func TestParseMaps(t *testing.T) {
+	p := newTestParser(t, "maps", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	userRef := `{"$ref": "#/components/schemas/example.com.maps.User"}`
+	assertJSON(t, "Catalog", schemas["example.com.maps.Catalog"].Properties, `{
+		"users": {"type": "object", "additionalProperties": `+userRef+`},
+		"counts": {"type": "object", "additionalProperties": {"type": "integer"}, "x-go-key-type": "int"},
+		"extra": {"type": "object", "additionalProperties": true},
+		"groups": {"type": "object", "additionalProperties": {"type": "array", "items": {"type": "integer"}}},
+		"flags": {"type": "object", "additionalProperties": {"type": "object", "additionalProperties": {"type": "boolean"}}},
+		"labels": {"type": "object", "$ref": "#/components/schemas/example.com.maps.Labels"},
+		"index": {"type": "object", "$ref": "#/components/schemas/example.com.maps.Index"},
+		"settings": {"type": "object", "additionalProperties": {"type": "object", "properties": {"on": {"type": "boolean"}}}}
+	}`)
+	assertJSON(t, "Labels", schemas["example.com.maps.Labels"], `{"type": "object", "additionalProperties": {"type": "string"}}`)
+	assertJSON(t, "Index", schemas["example.com.maps.Index"], `{"type": "object", "additionalProperties": `+userRef+`, "x-go-key-type": "int64"}`)
+	assertJSON(t, "201", p.OpenAPI.Paths["/catalog"].Get.Responses["201"].Content["application/json"].Schema,
+		`{"type": "object", "additionalProperties": `+userRef+`}`)
+
+	// integer keys are described by propertyNames in OpenAPI 3.1
+	p = newTestParser(t, "maps", "3.1", "", "")
+	p.convertToOpenAPI31()
+	assertJSON(t, "Index 3.1", p.OpenAPI.Components.Schemas["example.com.maps.Index"], `{
+		"type": "object", "additionalProperties": `+userRef+`, "propertyNames": {"pattern": "^-?[0-9]+$"}, "x-go-key-type": "int64"
+	}`)
+}
+
+func TestParseEmbedded(t *testing.T) {
+	p := newTestParser(t, "embedded", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	// the fields of Timestamps are copied with its required fields, and Base is composed
+	assertJSON(t, "Pet", schemas["example.com.embedded.Pet"], `{"allOf": [
+		{"$ref": "#/components/schemas/example.com.embedded.Base"},
+		{
+			"type": "object", "required": ["name", "createdAt"],
+			"properties": {
+				"name": {"type": "string"},
+				"createdAt": {"type": "string", "format": "date-time"},
+				"updatedAt": {"type": "string", "format": "date-time"}
+			}
+		}
+	]}`)
+	// a composed type is composed without the tag
+	assertJSON(t, "Dog", schemas["example.com.embedded.Dog"], `{"allOf": [
+		{"$ref": "#/components/schemas/example.com.embedded.Pet"},
+		{"type": "object", "properties": {"breed": {"type": "string"}}}
+	]}`)
+	assertJSON(t, "Tag", schemas["example.com.embedded.Tag"], `{
+		"type": "object", "required": ["id"],
+		"properties": {"label": {"type": "string"}, "id": {"type": "integer"}}
+	}`)
+
+	p, err := newParser(filepath.Join("testdata", "embedded_error"), "", "", "", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.parse()
+	if err == nil || !strings.Contains(err.Error(), "cannot parse embedded type Base") {
+		t.Errorf("embedded type with an invalid @Schema returned error %v", err)
+	}
+}
+
+func TestParsePolymorphism(t *testing.T) {
+	p := newTestParser(t, "polymorphism", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	ref := func(typeName string) string {
+		return `{"$ref": "#/components/schemas/example.com.polymorphism.` + typeName + `"}`
+	}
+	// the mapping uses the single const or enum value of kind, or else the type name
+	assertJSON(t, "Animal", schemas["example.com.polymorphism.Animal"], `{
+		"oneOf": [`+ref("Cat")+`, `+ref("Dog")+`, `+ref("Bird")+`],
+		"discriminator": {"propertyName": "kind", "mapping": {
+			"cat": "#/components/schemas/example.com.polymorphism.Cat",
+			"dog": "#/components/schemas/example.com.polymorphism.Dog",
+			"Bird": "#/components/schemas/example.com.polymorphism.Bird"
+		}}
+	}`)
+	// the implementations of Shape are found by its methods, with value and pointer receivers
+	assertJSON(t, "Zoo", schemas["example.com.polymorphism.Zoo"].Properties, `{
+		"star": `+ref("Animal")+`,
+		"animals": {"type": "array", "items": `+ref("Animal")+`},
+		"shapes": {"type": "array", "items": {"anyOf": [`+ref("Circle")+`, `+ref("Square")+`]}},
+		"pets": {"type": "array", "items": {
+			"oneOf": [`+ref("Cat")+`, `+ref("Bird")+`],
+			"discriminator": {"propertyName": "kind", "mapping": {
+				"cat": "#/components/schemas/example.com.polymorphism.Cat",
+				"Bird": "#/components/schemas/example.com.polymorphism.Bird"
+			}}
+		}},
+		"any": {"anyOf": [`+ref("Cat")+`, `+ref("Dog")+`]}
+	}`)
+	if _, ok := schemas["example.com.polymorphism.Line"]; ok {
+		t.Error("Line without Area is an implementation of Shape")
+	}
+
+	p, err := newParser(filepath.Join("testdata", "polymorphism_error"), "", "", "", "", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.parse()
+	if err == nil || !strings.Contains(err.Error(), "no implementation of Animal") {
+		t.Errorf("@OneOf of an empty interface returned error %v", err)
+	}
+}
+
+func TestGetTypeAsString(t *testing.T) {
+	p := &parser{TypeArgs: map[string]string{"T": "example.com.models.User"}}
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"int64", "int64"},
+		{"*models.User", "models.User"},
+		{"[]*User", "[]User"},
+		{"[5]int", "[]int"},
+		{"map[string][]int", "map[string][]int"},
+		{"interface{ Sound() string }", "interface{}"},
+		{"any", "interface{}"},
+		{"struct{ ID int }", "struct{}"},
+		{"[]struct{ ID int }", "[]struct{}"},
+		{"map[string]*struct{ ID int }", "map[string]struct{}"},
+		{"T", "example.com.models.User"},
+		{"Page[T]", "Page[example.com.models.User]"},
+		{"Pair[string, models.User]", "Pair[string,models.User]"},
+	}
+	for _, tt := range tests {
+		expr, err := goparser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.getTypeAsString(expr); got != tt.want {
+			t.Errorf("getTypeAsString(%s) = %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseAnonymous(t *testing.T) {
+	p := newTestParser(t, "anonymous", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	responseRef := func(path, status string) string {
+		return p.OpenAPI.Paths[path].Get.Responses[status].Content["application/json"].Schema.Ref
+	}
+	// the local types of handlers shadow the types of the package
+	for _, tt := range []struct{ path, status, want string }{
+		{"/users", "200", "#/components/schemas/example.com.anonymous.GetUsers.Response"},
+		{"/groups/{id}", "200", "#/components/schemas/example.com.anonymous.Handler.GetGroup.Response"},
+		{"/check", "200", "#/components/schemas/example.com.anonymous.Response"},
+	} {
+		if got := responseRef(tt.path, tt.status); got != tt.want {
+			t.Errorf("response %s of %s = %s, want %s", tt.status, tt.path, got, tt.want)
+		}
+	}
+	assertJSON(t, "GetUsers.Response", schemas["example.com.anonymous.GetUsers.Response"].Properties, `{
+		"meta": {"type": "object", "$ref": "#/components/schemas/example.com.anonymous.GetUsers.Meta"},
+		"paging": {"type": "object", "properties": {"next": {"type": "string", "nullable": true}}},
+		"data": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}},
+		"items": {"type": "array", "items": {"type": "object", "properties": {"label": {"type": "string"}}}},
+		"index": {"type": "object", "additionalProperties": {"type": "object", "properties": {"count": {"type": "integer"}}}},
+		"owner": {"type": "object", "nullable": true, "properties": {"name": {"type": "string"}}}
+	}`)
+	assertJSON(t, "GetGroup.Response", schemas["example.com.anonymous.Handler.GetGroup.Response"],
+		`{"type": "object", "properties": {"id": {"type": "integer"}}}`)
+	assertJSON(t, "Rows", schemas["example.com.anonymous.Rows"],
+		`{"type": "array", "items": {"type": "object", "properties": {"id": {"type": "integer"}}}}`)
+}
+
+func TestParseGenerics(t *testing.T) {
+	p := newTestParser(t, "generics", "", "", "")
+	schemas := p.OpenAPI.Components.Schemas
+	ref := func(id string) string {
+		return `{"$ref": "#/components/schemas/example.com.generics.` + id + `"}`
+	}
+	operation := p.OpenAPI.Paths["/users"].Get
+	assertJSON(t, "200", operation.Responses["200"].Content["application/json"].Schema, ref("core.Page_User"))
+	// dto.User has the same name as models.User, so its instantiation keeps the package
+	assertJSON(t, "201", operation.Responses["201"].Content["application/json"].Schema, ref("core.Page_example.com.generics.dto.User"))
+	assertJSON(t, "Page_User", schemas["example.com.generics.core.Page_User"], `{"type": "object", "properties": {
+		"items": {"type": "array", "items": `+ref("models.User")+`},
+		"next": {"type": "string"}
+	}}`)
+	assertJSON(t, "Page_dto.User", schemas["example.com.generics.core.Page_example.com.generics.dto.User"], `{"type": "object", "properties": {
+		"items": {"type": "array", "items": `+ref("dto.User")+`},
+		"next": {"type": "string"}
+	}}`)
+	objectRef := func(id string) string {
+		return `{"type": "object", "$ref": "#/components/schemas/example.com.generics.` + id + `"}`
+	}
+	assertJSON(t, "Result", schemas["example.com.generics.Result"].Properties, `{
+		"users": `+objectRef("core.Page_User")+`,
+		"lists": `+objectRef("core.Page_Array_User")+`,
+		"pair": `+objectRef("core.Pair_string_User")+`,
+		"nested": `+objectRef("core.Page_Page_User")+`
+	}`)
+	assertJSON(t, "Pair_string_User", schemas["example.com.generics.core.Pair_string_User"].Properties, `{
+		"key": {"type": "string"},
+		"value": `+objectRef("dto.User")+`
+	}`)
+	assertJSON(t, "Page_Page_User", schemas["example.com.generics.core.Page_Page_User"], `{"type": "object", "properties": {
+		"items": {"type": "array", "items": `+ref("core.Page_User")+`},
+		"next": {"type": "string"}
+	}}`)
+}
+
+func TestParseEnvelope(t *testing.T) {
+	p := newTestParser(t, "envelope", "", "", "")
+	ref := func(typeName string) string {
+		return `{"$ref": "#/components/schemas/example.com.envelope.models.` + typeName + `"}`
+	}
+	envelope := func(properties string) string {
+		return `{"allOf": [` + ref("Response") + `, {"type": "object", "properties": {` + properties + `}}]}`
+	}
+	operation := p.OpenAPI.Paths["/users"].Post
+	assertJSON(t, "body", operation.RequestBody.Content["application/json"].Schema, envelope(`"data": `+ref("User")))
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"200", envelope(`"data": ` + ref("User"))},
+		{"201", envelope(`"data": {"type": "array", "items": ` + ref("User") + `}, "meta": ` + ref("PageMeta"))},
+		{"202", envelope(`"data": {"allOf": [` + ref("Page") + `, {"type": "object", "properties": {
+			"items": {"type": "array", "items": ` + ref("User") + `}
+		}}]}`)},
+		{"203", envelope(`"data": {"type": "string"}, "meta": {"type": "object", "additionalProperties": {"type": "integer"}}`)},
+	}
+	for _, tt := range tests {
+		assertJSON(t, tt.status, operation.Responses[tt.status].Content["application/json"].Schema, tt.want)
+	}
+	assertJSON(t, "Response", p.OpenAPI.Components.Schemas["example.com.envelope.models.Response"].Properties, `{
+		"code": {"type": "integer"}, "data": {}, "meta": {}
+	}`)
+}
+
+func TestParseNullable(t *testing.T) {
+	p := newTestParser(t, "nullable", "", "", "")
+	ref := func(typeName string) string {
+		return `{"$ref": "#/components/schemas/example.com.nullable.` + typeName + `"}`
+	}
+	assertJSON(t, "3.0", p.OpenAPI.Components.Schemas["example.com.nullable.Profile"].Properties, `{
+		"nickname": {"type": "string", "nullable": true},
+		"leader": {"type": "object", "nullable": true, "allOf": [`+ref("User")+`]},
+		"status": {"type": "string", "nullable": true, "allOf": [`+ref("Status")+`]},
+		"tags": {"type": "array", "items": {"type": "string"}, "nullable": true},
+		"bio": {"type": "string", "nullable": true},
+		"age": {"type": "integer", "nullable": true},
+		"seen": {"type": "string", "format": "date-time", "nullable": true},
+		"score": {"type": "number", "nullable": true},
+		"friend": {"type": "object", "nullable": true, "allOf": [`+ref("User")+`]},
+		"motto": {"type": "string", "nullable": true},
+		"name": {"type": "string"}
+	}`)
+
+	p = newTestParser(t, "nullable", "3.1", "", "")
+	p.convertToOpenAPI31()
+	assertJSON(t, "3.1", p.OpenAPI.Components.Schemas["example.com.nullable.Profile"].Properties, `{
+		"nickname": {"type": ["string", "null"]},
+		"leader": {"anyOf": [`+ref("User")+`, {"type": "null"}]},
+		"status": {"anyOf": [`+ref("Status")+`, {"type": "null"}]},
+		"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+		"bio": {"type": ["string", "null"]},
+		"age": {"type": ["integer", "null"]},
+		"seen": {"type": ["string", "null"], "format": "date-time"},
+		"score": {"type": ["number", "null"]},
+		"friend": {"anyOf": [`+ref("User")+`, {"type": "null"}]},
+		"motto": {"type": ["string", "null"]},
+		"name": {"type": "string"}
+	}`)
+}
+
+func TestGetWellKnownTypeName(t *testing.T) {
+	p := &parser{PkgNameImportedPkgAlias: map[string]map[string][]string{"example.com/app": {
+		"neturl": {"net/url"},
+		"gouuid": {"github.com/google/uuid"},
+		"dbsql":  {"database/sql"},
+		"time":   {"time"},
+		"uuid":   {"example.com/app/uuid"},
+	}}}
+	tests := []struct {
+		typeName string
+		want     string
+		ok       bool
+	}{
+		{"neturl.URL", "url.URL", true},
+		{"gouuid.UUID", "uuid.UUID", true},
+		{"dbsql.NullString", "sql.NullString", true},
+		{"dbsql.Null[int64]", "sql.Null[int64]", true},
+		{"time.Time", "time.Time", true},
+		{"neturl.Values", "", false},
+		{"uuid.UUID", "", false},
+		{"url.URL", "", false},
+		{"User", "", false},
+		{"[]gouuid.UUID", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := p.getWellKnownTypeName("example.com/app", tt.typeName)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("getWellKnownTypeName(%s) = %s, %v, want %s, %v", tt.typeName, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseWellKnownTypes(t *testing.T) {
+	p := newTestParser(t, "wellknown", "", "", "")
+	// the types are found by the import paths of their packages, like neturl.URL
+	assertJSON(t, "Event", p.OpenAPI.Components.Schemas["example.com.wellknown.Event"].Properties, `{
+		"id": {"type": "string", "format": "uuid"},
+		"at": {"type": "string", "format": "date-time"},
+		"took": {"type": "integer", "format": "int64"},
+		"payload": {},
+		"count": {"type": "number"},
+		"source": {"type": "object"},
+		"link": {"type": "object", "$ref": "#/components/schemas/example.com.wellknown.Link"},
+		"addr": {"type": "string"},
+		"price": {"type": "string", "format": "decimal"},
+		"big": {"type": "integer", "nullable": true},
+		"data": {"type": "string", "format": "byte"},
+		"note": {"type": "string", "nullable": true},
+		"times": {"type": "array", "items": {"type": "string", "format": "date-time"}},
+		"prices": {"type": "object", "additionalProperties": {"type": "string", "format": "decimal"}}
+	}`)
+	assertJSON(t, "Link", p.OpenAPI.Components.Schemas["example.com.wellknown.Link"], `{"type": "object"}`)
+	operation := p.OpenAPI.Paths["/events/{id}"].Get
+	assertJSON(t, "parameters", operation.Parameters, `[
+		{"name": "id", "in": "path", "description": "Id.", "required": true, "schema": {"type": "string", "format": "uuid"}},
+		{"name": "since", "in": "query", "description": "Since.", "schema": {"type": "string", "format": "date-time"}}
+	]`)
+	assertJSON(t, "201", operation.Responses["201"].Content["application/json"].Schema, `{"type": "object"}`)
+	assertJSON(t, "202", operation.Responses["202"].Content["application/json"].Schema, `{"type": "array", "items": {"type": "string", "format": "uuid"}}`)
+	assertJSON(t, "X-Took", operation.Responses["200"].Headers["X-Took"].Schema, `{"type": "integer", "format": "int64"}`)
+}
+
+func TestParseTypeMappings(t *testing.T) {
+	p := newTestParser(t, "typemapping", "", filepath.Join("testdata", "typemapping", "types.yaml"), "")
+	// the types are found by the import paths of their packages, like m.Amount, and a mapping takes precedence over time.Duration
+	assertJSON(t, "Order", p.OpenAPI.Components.Schemas["example.com.typemapping.Order"].Properties, `{
+		"id": {"$ref": "#/components/schemas/example.com.typemapping.id.ULID"},
+		"total": {"type": "string", "$ref": "#/components/schemas/example.com.typemapping.money.Amount"},
+		"items": {"type": "array", "items": {"type": "string", "format": "decimal"}},
+		"taxes": {"type": "object", "additionalProperties": {"$ref": "#/components/schemas/example.com.typemapping.money.Amount"}},
+		"timeout": {"type": "string", "example": "1h30m"}
+	}`)
+	assertJSON(t, "ULID", p.OpenAPI.Components.Schemas["example.com.typemapping.id.ULID"], `{"$ref": "https://example.com/schemas.yaml#/ULID"}`)
+	assertJSON(t, "Amount", p.OpenAPI.Components.Schemas["example.com.typemapping.money.Amount"], `{"type": "string", "format": "decimal"}`)
+	operation := p.OpenAPI.Paths["/orders/{id}"].Get
+	assertJSON(t, "parameters", operation.Parameters, `[
+		{"name": "id", "in": "path", "description": "Id.", "required": true, "schema": {"$ref": "https://example.com/schemas.yaml#/ULID"}},
+		{"name": "min", "in": "query", "description": "Minimum.", "schema": {"type": "string", "format": "decimal"}}
+	]`)
+	assertJSON(t, "201", operation.Responses["201"].Content["application/json"].Schema, `{"$ref": "#/components/schemas/example.com.typemapping.money.Amount"}`)
+
+	// Swagger 2.0 keeps the external reference of the definition
+	swagger := p.convertToSwagger2()
+	assertJSON(t, "Swagger ULID", swagger.Definitions["example.com.typemapping.id.ULID"], `{"$ref": "https://example.com/schemas.yaml#/ULID"}`)
+}
+
+func TestParseMarshalers(t *testing.T) {
+	tests := []struct {
+		marshalerFallback string
+		money             string
+		warning           string
+	}{
+		{"", `{}`, "its schema is any value, set @Schema or --marshaler-fallback"},
+		{"{type: string, format: decimal}", `{"type": "string", "format": "decimal"}`, "its schema is the one of --marshaler-fallback"},
+	}
+	for _, tt := range tests {
+		var logs bytes.Buffer
+		log.SetOutput(&logs)
+		p := newTestParser(t, "marshalers", "", "", tt.marshalerFallback)
+		log.SetOutput(os.Stderr)
+		schemas := p.OpenAPI.Components.Schemas
+		// @Schema takes precedence over json.Marshaler
+		assertJSON(t, "Date", schemas["example.com.marshalers.Date"], `{"type": "string", "format": "date"}`)
+		assertJSON(t, "Money", schemas["example.com.marshalers.Money"], tt.money)
+		// the constants of a encoding.TextMarshaler are only its enum when they are strings
+		assertJSON(t, "Status", schemas["example.com.marshalers.Status"], `{"type": "string"}`)
+		assertJSON(t, "Color", schemas["example.com.marshalers.Color"], `{"type": "string", "enum": ["red", "blue"], "x-enum-varnames": ["ColorRed", "ColorBlue"]}`)
+		if !strings.Contains(logs.String(), "marshalers.Money implements json.Marshaler without @Schema, "+tt.warning) {
+			t.Errorf("marshaler fallback %q: warning %q, want %s", tt.marshalerFallback, logs.String(), tt.warning)
+		}
+	}
+
+	_, err := newParser(filepath.Join("testdata", "marshalers"), "", "", "", "", "{type: [", false)
+	if err == nil || !strings.Contains(err.Error(), "cannot parse marshaler fallback") {
+		t.Errorf("invalid marshaler fallback: error = %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// parseInterfaceSchemaObject parses the @OneOf, @AnyOf and @Discriminator comments of an interface type.
+//
+//	// @OneOf Cat Dog
+//	// @Discriminator kind
+//	type Animal interface {
+//		Sound() string
+//	}
+//
+// @OneOf or @AnyOf without types lists the structs which have all the methods of the interface.
+func (p *parser) parseInterfaceSchemaObject(pkgPath, pkgName string, schemaObject *SchemaObject, typeSpec *ast.TypeSpec) error {
+	if typeSpec.Doc == nil {
+		return nil
+	}
+	keyword, typeNames, discriminator := "", []string{}, ""
+	for _, astComment := range typeSpec.Doc.List {
+		comment := strings.TrimSpace(strings.TrimLeft(astComment.Text, "/"))
+		if len(comment) == 0 {
+			continue
+		}
+		attribute := strings.Fields(comment)[0]
+		value := strings.TrimSpace(comment[len(attribute):])
+		switch strings.ToLower(attribute) {
+		case "@oneof":
+			keyword = "oneOf"
+			typeNames = strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' || unicode.IsSpace(r) })
+		case "@anyof":
+			keyword = "anyOf"
+			typeNames = strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' || unicode.IsSpace(r) })
+		case "@discriminator":
+			discriminator = value
+		}
+	}
+	if keyword == "" {
+		return nil
+	}
+
+	var ids []string
+	if len(typeNames) == 0 {
+		ids = p.getInterfaceImplementations(typeSpec.Type.(*ast.InterfaceType))
+	} else {
+		for _, typeName := range typeNames {
+			id, err := p.registerType(pkgPath, pkgName, strings.TrimLeft(typeName, "*"))
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("parseInterfaceSchemaObject: no implementation of %s", typeSpec.Name.String())
+	}
+	p.setPolymorphicSchemaObject(schemaObject, keyword, ids, discriminator)
+	return nil
+}
+
+// parsePolymorphicTag parses the oneOf, anyOf and discriminator values of the goas tag of a field,
+// like goas:"oneOf=Cat|Dog,discriminator=kind". For a slice, they are applied to the items.
+func (p *parser) parsePolymorphicTag(pkgPath, pkgName string, fieldSchema *SchemaObject, tagValues []string) error {
+	keyword, typeNames, discriminator := "", []string{}, ""
+	for _, v := range tagValues {
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "oneOf", "anyOf":
+			keyword = kv[0]
+			typeNames = strings.Split(kv[1], "|")
+		case "discriminator":
+			discriminator = kv[1]
+		}
+	}
+	if keyword == "" {
+		return nil
+	}
+
+	ids := []string{}
+	for _, typeName := range typeNames {
+		id, err := p.registerType(pkgPath, pkgName, strings.TrimLeft(strings.TrimSpace(typeName), "*"))
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+	schema := fieldSchema
+	if fieldSchema.Type == "array" && fieldSchema.Items != nil {
+		// the items may be shared with a component
+		items := *fieldSchema.Items
+		fieldSchema.Items = &items
+		schema = fieldSchema.Items
+	}
+	p.setPolymorphicSchemaObject(schema, keyword, ids, discriminator)
+	return nil
+}
+
+// setPolymorphicSchemaObject sets oneOf or anyOf of the schema to the references of the registered types,
+// and the discriminator which maps the values of its property to them.
+func (p *parser) setPolymorphicSchemaObject(schema *SchemaObject, keyword string, ids []string, discriminator string) {
+	refs := []*SchemaObject{}
+	for _, id := range ids {
+		refs = append(refs, &SchemaObject{Ref: addSchemaRefLinkPrefix(id)})
+	}
+	schema.Type = ""
+	schema.Ref = ""
+	if keyword == "anyOf" {
+		schema.AnyOf = refs
+	} else {
+		schema.OneOf = refs
+	}
+
+	if discriminator == "" {
+		return
+	}
+	schema.Discriminator = &DiscriminatorObject{
+		PropertyName: discriminator,
+		Mapping:      map[string]string{},
+	}
+	for i, id := range ids {
+		schema.Discriminator.Mapping[p.getDiscriminatorValue(id, discriminator)] = refs[i].Ref
+	}
+}
+
+// getDiscriminatorValue returns the value of the discriminator property of the type,
+// which is its single enum value, like const:"cat", or else the type name.
+func (p *parser) getDiscriminatorValue(id, propertyName string) string {
+	if schema, ok := p.KnownIDSchema[id]; ok && schema.Properties != nil {
+		if v, ok := schema.Properties.Get(propertyName); ok {
+			if propertySchema, ok := v.(*SchemaObject); ok && len(propertySchema.Enum) == 1 {
+				return fmt.Sprint(propertySchema.Enum[0])
+			}
+		}
+	}
+	return id[strings.LastIndex(id, ".")+1:]
+}
+
+// getInterfaceImplementations registers the structs of the known packages which have all the methods
+// of the interface, and returns their ids. Methods of embedded interfaces are not taken into account.
+func (p *parser) getInterfaceImplementations(astInterfaceType *ast.InterfaceType) []string {
+	methodNames := []string{}
+	if astInterfaceType.Methods != nil {
+		for _, astField := range astInterfaceType.Methods.List {
+			if _, ok := astField.Type.(*ast.FuncType); !ok {
+				continue
+			}
+			for _, name := range astField.Names {
+				methodNames = append(methodNames, name.Name)
+			}
+		}
+	}
+	// every type implements an empty interface
+	if len(methodNames) == 0 {
+		return nil
+	}
+
+	ids := []string{}
+	visited := map[string]struct{}{}
+	for _, knownPkg := range p.KnownPkgs {
+		if _, ok := visited[knownPkg.Name]; ok {
+			continue
+		}
+		visited[knownPkg.Name] = struct{}{}
+
+		typeNames := []string{}
+		for typeName, typeSpec := range p.TypeSpecs[knownPkg.Name] {
+			if _, ok := typeSpec.Type.(*ast.StructType); !ok || strings.Contains(typeName, "@") {
+				continue
+			}
+			implemented := true
+			for _, methodName := range methodNames {
+				if !isInStringList(p.TypeMethods[knownPkg.Name][typeName], methodName) {
+					implemented = false
+					break
+				}
+			}
+			if implemented {
+				typeNames = append(typeNames, typeName)
+			}
+		}
+		sort.Strings(typeNames)
+		for _, typeName := range typeNames {
+			id, err := p.registerType(knownPkg.Path, knownPkg.Name, typeName)
+			if err != nil {
+				p.debug("getInterfaceImplementations err:", err)
+				continue
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const SwaggerVersion20 = "2.0"
+
+type SwaggerObject struct {
+	Swagger  string                            `json:"swagger"` // Required
+	Info     InfoObject                        `json:"info"`    // Required
+	Host     string                            `json:"host,omitempty"`
+	BasePath string                            `json:"basePath,omitempty"`
+	Schemes  []string                          `json:"schemes,omitempty"`
+	Paths    map[string]*SwaggerPathItemObject `json:"paths"` // Required
+
+	Definitions         map[string]*SchemaObject                `json:"definitions,omitempty"`
+	SecurityDefinitions map[string]*SwaggerSecuritySchemeObject `json:"securityDefinitions,omitempty"`
+	Security            []map[string][]string                   `json:"security,omitempty"`
+}
+
+type SwaggerPathItemObject struct {
+	Get     *SwaggerOperationObject `json:"get,omitempty"`
+	Post    *SwaggerOperationObject `json:"post,omitempty"`
+	Patch   *SwaggerOperationObject `json:"patch,omitempty"`
+	Put     *SwaggerOperationObject `json:"put,omitempty"`
+	Delete  *SwaggerOperationObject `json:"delete,omitempty"`
+	Options *SwaggerOperationObject `json:"options,omitempty"`
+	Head    *SwaggerOperationObject `json:"head,omitempty"`
+}
+
+type SwaggerOperationObject struct {
+	Tags        []string                          `json:"tags,omitempty"`
+	Summary     string                            `json:"summary,omitempty"`
+	Description string                            `json:"description,omitempty"`
+	OperationID string                            `json:"operationId,omitempty"`
+	Consumes    []string                          `json:"consumes,omitempty"`
+	Produces    []string                          `json:"produces,omitempty"`
+	Parameters  []SwaggerParameterObject          `json:"parameters,omitempty"`
+	Responses   map[string]*SwaggerResponseObject `json:"responses"` // Required
+	Deprecated  bool                              `json:"deprecated,omitempty"`
+	Sunset      string                            `json:"x-sunset,omitempty"`
+	Security    *[]map[string][]string            `json:"security,omitempty"`
+}
+
+type SwaggerParameterObject struct {
+	Name        string `json:"name"` // Required
+	In          string `json:"in"`   // Required. Possible values are "query", "header", "path", "formData" or "body"
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+
+	// body
+	Schema *SchemaObject `json:"schema,omitempty"`
+
+	// other than body
+	Type             string        `json:"type,omitempty"`
+	Format           string        `json:"format,omitempty"`
+	Items            *SchemaObject `json:"items,omitempty"`
+	CollectionFormat string        `json:"collectionFormat,omitempty"`
+	Enum             []interface{} `json:"enum,omitempty"`
+}
+
+type SwaggerResponseObject struct {
+	Description string                          `json:"description"` // Required
+	Schema      *SchemaObject                   `json:"schema,omitempty"`
+	Headers     map[string]*SwaggerHeaderObject `json:"headers,omitempty"`
+}
+
+type SwaggerHeaderObject struct {
+	Description string        `json:"description,omitempty"`
+	Type        string        `json:"type"` // Required
+	Format      string        `json:"format,omitempty"`
+	Items       *SchemaObject `json:"items,omitempty"`
+}
+
+type SwaggerSecuritySchemeObject struct {
+	Type        string `json:"type"` // Required. Possible values are "basic", "apiKey" or "oauth2"
+	Description string `json:"description,omitempty"`
+
+	// apiKey
+	Name string `json:"name,omitempty"`
+	In   string `json:"in,omitempty"`
+
+	// oauth2
+	Flow             string            `json:"flow,omitempty"`
+	AuthorizationUrl string            `json:"authorizationUrl,omitempty"`
+	TokenUrl         string            `json:"tokenUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"`
+}
+
+// convertToSwagger2 converts the parsed document into a Swagger 2.0 document.
+// Schemas are shared with the parsed document, so it must only be called before output.
+func (p *parser) convertToSwagger2() *SwaggerObject {
+	swagger := &SwaggerObject{
+		Swagger:             SwaggerVersion20,
+		Info:                p.OpenAPI.Info,
+		Paths:               map[string]*SwaggerPathItemObject{},
+		Definitions:         p.OpenAPI.Components.Schemas,
+		SecurityDefinitions: map[string]*SwaggerSecuritySchemeObject{},
+		Security:            p.OpenAPI.Security,
+	}
+
+	p.OpenAPI.WalkSchemaObjects(func(schema *SchemaObject) {
+		// external references, like the ones of --type-mapping, are kept
+		if strings.HasPrefix(schema.Ref, "#/components/schemas/") {
+			schema.Ref = "#/definitions/" + trimeSchemaRefLinkPrefix(schema.Ref)
+		}
+		// nullable and deprecated are not supported by Swagger 2.0
+		schema.Nullable = false
+		schema.Deprecated = false
+		// neither are oneOf and anyOf, the schema accepts any value instead
+		if len(schema.OneOf) != 0 || len(schema.AnyOf) != 0 {
+			schema.OneOf, schema.AnyOf, schema.Discriminator = nil, nil, nil
+		}
+	})
+
+	p.convertServersToSwagger2(swagger)
+
+	for name, scheme := range p.OpenAPI.Components.SecuritySchemes {
+		securityScheme := p.convertSecuritySchemeToSwagger2(name, scheme)
+		if securityScheme != nil {
+			swagger.SecurityDefinitions[name] = securityScheme
+		}
+	}
+
+	for path, pathItem := range p.OpenAPI.Paths {
+		if pathItem.Trace != nil {
+			p.debugf("convertToSwagger2 ignores trace operation of %s", path)
+		}
+		swagger.Paths[path] = &SwaggerPathItemObject{
+			Get:     p.convertOperationToSwagger2(pathItem.Get),
+			Post:    p.convertOperationToSwagger2(pathItem.Post),
+			Patch:   p.convertOperationToSwagger2(pathItem.Patch),
+			Put:     p.convertOperationToSwagger2(pathItem.Put),
+			Delete:  p.convertOperationToSwagger2(pathItem.Delete),
+			Options: p.convertOperationToSwagger2(pathItem.Options),
+			Head:    p.convertOperationToSwagger2(pathItem.Head),
+		}
+	}
+
+	return swagger
+}
+
+// convertServersToSwagger2 sets host and basePath from the first server,
+// and schemes from all the servers with the same host.
+func (p *parser) convertServersToSwagger2(swagger *SwaggerObject) {
+	if len(p.OpenAPI.Servers) == 0 {
+		return
+	}
+	u, err := url.Parse(p.OpenAPI.Servers[0].URL)
+	if err != nil {
+		p.debugf("convertServersToSwagger2 cannot parse server url %s: %s", p.OpenAPI.Servers[0].URL, err)
+		return
+	}
+	swagger.Host = u.Host
+	swagger.BasePath = u.Path
+	if swagger.BasePath == "" {
+		swagger.BasePath = "/"
+	}
+	for i := range p.OpenAPI.Servers {
+		su, err := url.Parse(p.OpenAPI.Servers[i].URL)
+		if err != nil || su.Host != u.Host || su.Scheme == "" {
+			continue
+		}
+		if !isInStringList(swagger.Schemes, su.Scheme) {
+			swagger.Schemes = append(swagger.Schemes, su.Scheme)
+		}
+	}
+}
+
+func (p *parser) convertSecuritySchemeToSwagger2(name string, scheme *SecuritySchemeObject) *SwaggerSecuritySchemeObject {
+	securityScheme := &SwaggerSecuritySchemeObject{
+		Description: scheme.Description,
+	}
+	switch scheme.Type {
+	case "http":
+		if strings.ToLower(scheme.Scheme) == "basic" {
+			securityScheme.Type = "basic"
+			break
+		}
+		// other http schemes, like bearer, are sent in the Authorization header
+		securityScheme.Type = "apiKey"
+		securityScheme.In = "header"
+		securityScheme.Name = "Authorization"
+	case "apiKey":
+		if scheme.In != "header" && scheme.In != "query" {
+			p.debugf("convertSecuritySchemeToSwagger2 ignores %s, apiKey in %s is not supported", name, scheme.In)
+			return nil
+		}
+		securityScheme.Type = "apiKey"
+		securityScheme.In = scheme.In
+		securityScheme.Name = scheme.Name
+	case "oauth2":
+		securityScheme.Type = "oauth2"
+		var flow *SecuritySchemeOauthFlowObject
+		switch {
+		case scheme.OAuthFlows.Implicit != nil:
+			securityScheme.Flow, flow = "implicit", scheme.OAuthFlows.Implicit
+		case scheme.OAuthFlows.AuthorizationCode != nil:
+			securityScheme.Flow, flow = "accessCode", scheme.OAuthFlows.AuthorizationCode
+		case scheme.OAuthFlows.ResourceOwnerPassword != nil:
+			securityScheme.Flow, flow = "password", scheme.OAuthFlows.ResourceOwnerPassword
+		case scheme.OAuthFlows.ClientCredentials != nil:
+			securityScheme.Flow, flow = "application", scheme.OAuthFlows.ClientCredentials
+		default:
+			return nil
+		}
+		securityScheme.AuthorizationUrl = flow.AuthorizationUrl
+		securityScheme.TokenUrl = flow.TokenUrl
+		securityScheme.Scopes = flow.Scopes
+	default:
+		p.debugf("convertSecuritySchemeToSwagger2 ignores %s, type %s is not supported", name, scheme.Type)
+		return nil
+	}
+	return securityScheme
+}
+
+func (p *parser) convertOperationToSwagger2(operation *OperationObject) *SwaggerOperationObject {
+	if operation == nil {
+		return nil
+	}
+	swaggerOperation := &SwaggerOperationObject{
+		Tags:        operation.Tags,
+		Summary:     operation.Summary,
+		Description: operation.Description,
+		OperationID: operation.OperationID,
+		Parameters:  []SwaggerParameterObject{},
+		Responses:   map[string]*SwaggerResponseObject{},
+		Deprecated:  operation.Deprecated,
+		Sunset:      operation.Sunset,
+		Security:    operation.Security,
+	}
+
+	for i := range operation.Parameters {
+		parameter := &operation.Parameters[i]
+		if parameter.In == "cookie" {
+			p.debugf("convertOperationToSwagger2 ignores cookie parameter %s", parameter.Name)
+			continue
+		}
+		swaggerParameter := SwaggerParameterObject{
+			Name:        parameter.Name,
+			In:          parameter.In,
+			Description: parameter.Description,
+			Required:    parameter.Required,
+		}
+		p.setSwagger2ParameterType(&swaggerParameter, parameter.Schema)
+		if swaggerParameter.Type == "array" {
+			swaggerParameter.CollectionFormat = getSwagger2CollectionFormat(parameter)
+		}
+		swaggerOperation.Parameters = append(swaggerOperation.Parameters, swaggerParameter)
+	}
+
+	if operation.RequestBody != nil {
+		contentTypes := sortedContentTypes(operation.RequestBody.Content)
+		swaggerOperation.Consumes = contentTypes
+		for _, contentType := range contentTypes {
+			mediaType := operation.RequestBody.Content[contentType]
+			if contentType != ContentTypeForm && contentType != ContentTypeFormUrlencoded {
+				swaggerOperation.Parameters = append(swaggerOperation.Parameters, SwaggerParameterObject{
+					Name:        "body",
+					In:          "body",
+					Description: operation.RequestBody.Description,
+					Required:    operation.RequestBody.Required,
+					Schema:      &mediaType.Schema,
+				})
+				break
+			}
+			if mediaType.Schema.Properties == nil {
+				continue
+			}
+			for _, name := range mediaType.Schema.Properties.Keys() {
+				v, _ := mediaType.Schema.Properties.Get(name)
+				propertySchema, ok := v.(*SchemaObject)
+				if !ok {
+					continue
+				}
+				swaggerParameter := SwaggerParameterObject{
+					Name:        name,
+					In:          "formData",
+					Description: propertySchema.Description,
+					Required:    isInStringList(mediaType.Schema.Required, name),
+				}
+				p.setSwagger2ParameterType(&swaggerParameter, propertySchema)
+				swaggerOperation.Parameters = append(swaggerOperation.Parameters, swaggerParameter)
+			}
+			break
+		}
+	}
+
+	for status, response := range operation.Responses {
+		swaggerResponse := &SwaggerResponseObject{
+			Description: response.Description,
+		}
+		contentTypes := sortedContentTypes(response.Content)
+		for _, contentType := range contentTypes {
+			if !isInStringList(swaggerOperation.Produces, contentType) {
+				swaggerOperation.Produces = append(swaggerOperation.Produces, contentType)
+			}
+		}
+		if len(contentTypes) != 0 {
+			swaggerResponse.Schema = &response.Content[contentTypes[0]].Schema
+		}
+		for name, header := range response.Headers {
+			// a header needs a type, which an external reference does not have
+			schema := p.resolveSwagger2Schema(header.Schema)
+			if schema == nil || schema.Type == "" {
+				p.debugf("convertOperationToSwagger2 ignores header %s without type", name)
+				continue
+			}
+			if swaggerResponse.Headers == nil {
+				swaggerResponse.Headers = map[string]*SwaggerHeaderObject{}
+			}
+			swaggerResponse.Headers[name] = &SwaggerHeaderObject{
+				Description: header.Description,
+				Type:        schema.Type,
+				Format:      schema.Format,
+				Items:       schema.Items,
+			}
+		}
+		swaggerOperation.Responses[status] = swaggerResponse
+	}
+
+	return swaggerOperation
+}
+
+// setSwagger2ParameterType sets type, format and items of a non-body parameter,
+// which do not use schema in Swagger 2.0.
+func (p *parser) setSwagger2ParameterType(parameter *SwaggerParameterObject, schema *SchemaObject) {
+	schema = p.resolveSwagger2Schema(schema)
+	if schema == nil {
+		return
+	}
+	if parameter.In == "formData" && schema.Type == "string" && schema.Format == "binary" {
+		parameter.Type = "file"
+		return
+	}
+	parameter.Type = schema.Type
+	parameter.Format = schema.Format
+	parameter.Items = schema.Items
+	parameter.Enum = schema.Enum
+}
+
+// getSwagger2CollectionFormat converts style and explode of an array parameter into collectionFormat.
+func getSwagger2CollectionFormat(parameter *ParameterObject) string {
+	style := parameter.Style
+	if style == "" {
+		// default style of query and cookie is form, of path and header is simple
+		style = "simple"
+		if parameter.In == "query" || parameter.In == "cookie" {
+			style = "form"
+		}
+	}
+	explode := style == "form"
+	if parameter.Explode != nil {
+		explode = *parameter.Explode
+	}
+	switch style {
+	case "form":
+		if explode {
+			return "multi"
+		}
+		return "csv"
+	case "spaceDelimited":
+		return "ssv"
+	case "pipeDelimited":
+		return "pipes"
+	}
+	return "csv"
+}
+
+// resolveSwagger2Schema returns the definition of a referenced schema,
+// for the parameters and headers which can not use $ref in Swagger 2.0.
+func (p *parser) resolveSwagger2Schema(schema *SchemaObject) *SchemaObject {
+	if schema == nil || !strings.HasPrefix(schema.Ref, "#/definitions/") {
+		return schema
+	}
+	refSchema, ok := p.OpenAPI.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/definitions/")]
+	if !ok {
+		return nil
+	}
+	return refSchema
+}
+
+// sortedContentTypes returns the content types with application/json first.
+func sortedContentTypes(content map[string]*MediaTypeObject) []string {
+	contentTypes := []string{}
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Slice(contentTypes, func(i, j int) bool {
+		if (contentTypes[i] == ContentTypeJson) != (contentTypes[j] == ContentTypeJson) {
+			return contentTypes[i] == ContentTypeJson
+		}
+		return contentTypes[i] < contentTypes[j]
+	})
+	return contentTypes
+}
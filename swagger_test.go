@@ -0,0 +1,185 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+)
+
+func TestConvertToSwagger2(t *testing.T) {
+	falsePtr := func() *bool { b := false; return &b }
+	status := &SchemaObject{Type: "string", Enum: []interface{}{"active", "banned"}}
+	formProperties := orderedmap.New()
+	formProperties.Set("name", &SchemaObject{Type: "string", Description: "Name."})
+	formProperties.Set("avatar", &SchemaObject{Type: "string", Format: "binary"})
+
+	tests := []struct {
+		name    string
+		openAPI OpenAPIObject
+		want    *SwaggerObject
+	}{
+		{
+			name: "servers",
+			openAPI: OpenAPIObject{
+				Servers: []ServerObject{
+					{URL: "https://api.example.com/v1"},
+					{URL: "http://api.example.com/v1"},
+					{URL: "https://staging.example.com/v1"},
+				},
+			},
+			want: &SwaggerObject{
+				Host:     "api.example.com",
+				BasePath: "/v1",
+				Schemes:  []string{"https", "http"},
+			},
+		},
+		{
+			name: "security schemes",
+			openAPI: OpenAPIObject{
+				Components: ComponentsOjbect{
+					SecuritySchemes: map[string]*SecuritySchemeObject{
+						"basic":  {Type: "http", Scheme: "basic"},
+						"bearer": {Type: "http", Scheme: "bearer"},
+						"key":    {Type: "apiKey", In: "query", Name: "key"},
+						"cookie": {Type: "apiKey", In: "cookie", Name: "session"},
+					},
+				},
+			},
+			want: &SwaggerObject{
+				SecurityDefinitions: map[string]*SwaggerSecuritySchemeObject{
+					"basic":  {Type: "basic"},
+					"bearer": {Type: "apiKey", In: "header", Name: "Authorization"},
+					"key":    {Type: "apiKey", In: "query", Name: "key"},
+				},
+			},
+		},
+		{
+			name: "definitions",
+			openAPI: OpenAPIObject{
+				Components: ComponentsOjbect{
+					Schemas: map[string]*SchemaObject{
+						"User": {Type: "object", Nullable: true, Deprecated: true, Items: &SchemaObject{Ref: "#/components/schemas/Group"}},
+						"ULID": {Ref: "https://example.com/schemas.yaml#/ULID"},
+						"Pet":  {OneOf: []*SchemaObject{{Type: "string"}, {Type: "integer"}}},
+					},
+				},
+			},
+			want: &SwaggerObject{
+				Definitions: map[string]*SchemaObject{
+					"User": {Type: "object", Items: &SchemaObject{Ref: "#/definitions/Group"}},
+					"ULID": {Ref: "https://example.com/schemas.yaml#/ULID"},
+					"Pet":  {},
+				},
+			},
+		},
+		{
+			name: "parameters and json body",
+			openAPI: OpenAPIObject{
+				Paths: PathsObject{
+					"/users": {Post: &OperationObject{
+						OperationID: "users_PostUser",
+						Parameters: []ParameterObject{
+							{Name: "status", In: "query", Required: true, Schema: &SchemaObject{Ref: "#/components/schemas/Status"}},
+							{Name: "ids", In: "query", Explode: falsePtr(), Schema: &SchemaObject{Type: "array", Items: &SchemaObject{Type: "integer"}}},
+							{Name: "session", In: "cookie", Schema: &SchemaObject{Type: "string"}},
+						},
+						RequestBody: &RequestBodyObject{
+							Required: true,
+							Content: map[string]*MediaTypeObject{
+								ContentTypeJson: {Schema: SchemaObject{Ref: "#/components/schemas/User"}},
+							},
+						},
+						Responses: ResponsesObject{
+							"200": {
+								Description: "OK",
+								Content: map[string]*MediaTypeObject{
+									ContentTypeJson: {Schema: SchemaObject{Ref: "#/components/schemas/User"}},
+								},
+								Headers: map[string]*HeaderObject{
+									"X-Status": {Schema: &SchemaObject{Ref: "#/components/schemas/Status"}},
+									"X-Trace":  {Schema: &SchemaObject{Ref: "https://example.com/schemas.yaml#/Trace"}},
+									"X-Group":  {Schema: &SchemaObject{Ref: "#/components/schemas/Group"}},
+								},
+							},
+						},
+					}},
+				},
+				Components: ComponentsOjbect{
+					Schemas: map[string]*SchemaObject{"Status": status},
+				},
+			},
+			want: &SwaggerObject{
+				Paths: map[string]*SwaggerPathItemObject{
+					"/users": {Post: &SwaggerOperationObject{
+						OperationID: "users_PostUser",
+						Consumes:    []string{ContentTypeJson},
+						Produces:    []string{ContentTypeJson},
+						Parameters: []SwaggerParameterObject{
+							{Name: "status", In: "query", Required: true, Type: "string", Enum: []interface{}{"active", "banned"}},
+							{Name: "ids", In: "query", Type: "array", Items: &SchemaObject{Type: "integer"}, CollectionFormat: "csv"},
+							{Name: "body", In: "body", Required: true, Schema: &SchemaObject{Ref: "#/definitions/User"}},
+						},
+						Responses: map[string]*SwaggerResponseObject{
+							"200": {
+								Description: "OK",
+								Schema:      &SchemaObject{Ref: "#/definitions/User"},
+								Headers: map[string]*SwaggerHeaderObject{
+									"X-Status": {Type: "string"},
+								},
+							},
+						},
+					}},
+				},
+				Definitions: map[string]*SchemaObject{"Status": status},
+			},
+		},
+		{
+			name: "form body",
+			openAPI: OpenAPIObject{
+				Paths: PathsObject{
+					"/avatar": {Put: &OperationObject{
+						RequestBody: &RequestBodyObject{
+							Content: map[string]*MediaTypeObject{
+								ContentTypeForm: {Schema: SchemaObject{Type: "object", Required: []string{"avatar"}, Properties: formProperties}},
+							},
+						},
+						Responses: ResponsesObject{
+							"204": {Description: "No Content"},
+						},
+					}},
+				},
+			},
+			want: &SwaggerObject{
+				Paths: map[string]*SwaggerPathItemObject{
+					"/avatar": {Put: &SwaggerOperationObject{
+						Consumes: []string{ContentTypeForm},
+						Parameters: []SwaggerParameterObject{
+							{Name: "name", In: "formData", Description: "Name.", Type: "string"},
+							{Name: "avatar", In: "formData", Required: true, Type: "file"},
+						},
+						Responses: map[string]*SwaggerResponseObject{
+							"204": {Description: "No Content"},
+						},
+					}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		p := &parser{OpenAPI: tt.openAPI}
+		got := p.convertToSwagger2()
+
+		want := tt.want
+		want.Swagger = SwaggerVersion20
+		if want.Paths == nil {
+			want.Paths = map[string]*SwaggerPathItemObject{}
+		}
+		if want.SecurityDefinitions == nil {
+			want.SecurityDefinitions = map[string]*SwaggerSecuritySchemeObject{}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"time"
+
+	"example.com/aliases/models"
+)
+
+type Color = string
+
+type Member = models.User
+
+type Members = []Member
+
+type Timestamp = time.Time
+
+type Point = struct {
+	X int `json:"x"`
+}
+
+type Team struct {
+	Color   Color            `json:"color"`
+	Tier    models.Tier      `json:"tier"`
+	Leader  *Member          `json:"leader"`
+	Members Members          `json:"members"`
+	Scores  map[string]Color `json:"scores"`
+	Created Timestamp        `json:"created"`
+	Origin  Point            `json:"origin"`
+	models.Base
+}
+
+// @Title Get a team.
+// @Param color query Color false "Color."
+// @Route /teams/{id} [get]
+// @Success 200 object Team "Team."
+// @Success 201 object Member "Member."
+// @Header 200 X-Color Color "Color."
+func GetTeam() {}
@@ -0,0 +1,15 @@
+package models
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Tier = Level
+
+type Level int
+
+type Base = base
+
+type base struct {
+	ID int64 `json:"id"`
+}
@@ -0,0 +1,56 @@
+package main
+
+type User struct {
+	Name string `json:"name"`
+}
+
+// Response is shadowed by the local types of the handlers.
+type Response struct {
+	OK bool `json:"ok"`
+}
+
+type Rows []struct {
+	ID int64 `json:"id"`
+}
+
+// @Title Get users.
+// @Route /users [get]
+// @Success 200 object Response "Users."
+func GetUsers() {
+	type Meta struct {
+		Total int `json:"total"`
+	}
+	type Response struct {
+		Meta   Meta `json:"meta"`
+		Paging struct {
+			Next *string `json:"next"`
+		} `json:"paging"`
+		Data  []User `json:"data"`
+		Items []struct {
+			Label string `json:"label"`
+		} `json:"items"`
+		Index map[string]*struct {
+			Count int `json:"count"`
+		} `json:"index"`
+		Owner *struct {
+			Name string `json:"name"`
+		} `json:"owner"`
+	}
+}
+
+type Handler struct{}
+
+// @Title Get a group.
+// @Route /groups/{id} [get]
+// @Success 200 object Response "Group."
+func (h *Handler) GetGroup() {
+	type Response struct {
+		ID int64 `json:"id"`
+	}
+}
+
+// @Title Check.
+// @Route /check [get]
+// @Success 200 object Response "Check."
+// @Success 201 object Rows "Rows."
+func Check() {}
@@ -0,0 +1,10 @@
+package main
+
+type User struct {
+	Name string `json:"name"`
+}
+
+// @Title Get user.
+// @Success 200 {object} User "The user."
+// @Route /users [get]
+func GetUser() {}
@@ -0,0 +1,6 @@
+package main
+
+// @Version 1.0.0
+// @Title Basic API
+// @Server https://api.example.com/v1 Production
+func main() {}
@@ -0,0 +1,3 @@
+module example.com/deprecated
+
+go 1.12
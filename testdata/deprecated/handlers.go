@@ -0,0 +1,32 @@
+package main
+
+type User struct {
+	Name string `json:"name"`
+
+	// Deprecated: use Name.
+	FullName string `json:"full_name"`
+
+	Nick string `json:"nick"` // Deprecated: use Name.
+}
+
+// @Title List users.
+// @Success 200 {array} User
+// @Route /users [get]
+//
+// Deprecated: use ListUsers2,
+// which is paged.
+func ListUsers() {}
+
+// @Title List old users.
+// @Description Lists the old users.
+// @Deprecated use ListUsers2
+// @Sunset 2027-01-31
+// @Success 200 {array} User
+// @Route /old-users [get]
+func ListOldUsers() {}
+
+// @Title List users 2.
+// @Deprecated
+// @Success 200 {array} User
+// @Route /users2 [get]
+func ListUsers2() {}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"time"
+)
+
+type Base struct {
+	ID int64 `json:"id" validate:"required"`
+}
+
+type Timestamps struct {
+	CreatedAt time.Time `json:"createdAt" validate:"required"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Pet struct {
+	Base `goas:"allOf"`
+	Timestamps
+	Name string `json:"name" validate:"required"`
+}
+
+type Dog struct {
+	Pet
+	Breed string `json:"breed"`
+}
+
+type Tag struct {
+	*Base
+	Label string `json:"label"`
+}
+
+// @Title Get a dog.
+// @Route /dogs/{id} [get]
+// @Success 200 object Dog "Dog."
+// @Success 201 object Tag "Tag."
+func GetDog() {}
@@ -0,0 +1,3 @@
+module example.com/embedded_error
+
+go 1.12
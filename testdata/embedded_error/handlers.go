@@ -0,0 +1,16 @@
+package main
+
+// @Schema {type: [string
+type Base struct {
+	ID int64 `json:"id"`
+}
+
+type Pet struct {
+	Base `goas:"allOf"`
+	Name string `json:"name"`
+}
+
+// @Title Get a pet.
+// @Route /pets/{id} [get]
+// @Success 200 object Pet "Pet."
+func GetPet() {}
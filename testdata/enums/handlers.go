@@ -0,0 +1,10 @@
+package main
+
+import (
+	_ "example.com/enums/models"
+)
+
+// @Title Get a task.
+// @Route /tasks/{id} [get]
+// @Success 200 object models.Task "Task."
+func GetTask() {}
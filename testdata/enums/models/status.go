@@ -0,0 +1,9 @@
+package models
+
+const (
+	StatusClosed Status = "closed"
+	StatusOther         = Status("o" + "ther")
+)
+
+// Green is declared after the constants of Status.
+const ColorGreen Color = "green"
@@ -0,0 +1,39 @@
+package models
+
+type Task struct {
+	Priority Priority `json:"priority"`
+	Flag     Flag     `json:"flag"`
+	Status   Status   `json:"status"`
+	Kind     string   `json:"kind" const:"task"`
+	Size     int      `json:"size" enum:"1, 2, x"`
+	Colors   []Color  `json:"colors" enum:"red,blue"`
+}
+
+type Priority int
+
+const (
+	_ Priority = iota
+	PriorityLow
+	PriorityHigh
+	PriorityMax = PriorityHigh
+)
+
+type Flag uint
+
+const (
+	FlagRead Flag = 1 << iota
+	FlagWrite
+	FlagExec
+)
+
+type Status string
+
+const (
+	StatusOpen = Status("open")
+	Unrelated  = "unrelated"
+	timeout    = 10
+)
+
+type Color string
+
+const ColorRed Color = "red"
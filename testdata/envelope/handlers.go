@@ -0,0 +1,14 @@
+package main
+
+import (
+	_ "example.com/envelope/models"
+)
+
+// @Title Create a user.
+// @Param user body models.Response{data=models.User} true "User."
+// @Route /users [post]
+// @Success 200 object models.Response{data=models.User} "User."
+// @Success 201 object models.Response{data=[]models.User,meta=models.PageMeta} "Users."
+// @Success 202 object models.Response{data=models.Page{items=[]models.User}} "Page."
+// @Success 203 object models.Response{data=string,meta=map[string]int} "Text."
+func CreateUser() {}
@@ -0,0 +1,20 @@
+package models
+
+type Response struct {
+	Code int         `json:"code"`
+	Data interface{} `json:"data"`
+	Meta interface{} `json:"meta"`
+}
+
+type Page struct {
+	Items interface{} `json:"items"`
+	Next  string      `json:"next"`
+}
+
+type PageMeta struct {
+	Total int `json:"total"`
+}
+
+type User struct {
+	Name string `json:"name"`
+}
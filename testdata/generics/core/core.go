@@ -0,0 +1,11 @@
+package core
+
+type Page[T any] struct {
+	Items []T    `json:"items"`
+	Next  string `json:"next"`
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
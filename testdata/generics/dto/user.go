@@ -0,0 +1,5 @@
+package dto
+
+type User struct {
+	Email string `json:"email"`
+}
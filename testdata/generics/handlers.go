@@ -0,0 +1,21 @@
+package main
+
+import (
+	"example.com/generics/core"
+	"example.com/generics/dto"
+	"example.com/generics/models"
+)
+
+type Result struct {
+	Users  core.Page[models.User]            `json:"users"`
+	Lists  core.Page[[]models.User]          `json:"lists"`
+	Pair   core.Pair[string, dto.User]       `json:"pair"`
+	Nested core.Page[core.Page[models.User]] `json:"nested"`
+}
+
+// @Title List users.
+// @Route /users [get]
+// @Success 200 object core.Page[models.User] "Users."
+// @Success 201 object core.Page[dto.User] "Users of dto."
+// @Success 202 object Result "Result."
+func ListUsers() {}
@@ -0,0 +1,16 @@
+package main
+
+import "time"
+
+type User struct {
+	Name string `json:"name"`
+}
+
+// @Title Get user.
+// @Header 200 X-Rate-Limit int "Requests per hour."
+// @Success 200 {object} User "The user."
+// @Header 200 X-Expires time.Time
+// @Header 200 X-Tags []string "Tags."
+// @Header 429 Retry-After int64 "Seconds to wait."
+// @Route /user [get]
+func GetUser() {}
@@ -0,0 +1,28 @@
+package main
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Labels map[string]string
+
+type Index map[int64]*User
+
+type Catalog struct {
+	Users    map[string]User            `json:"users"`
+	Counts   map[int]int64              `json:"counts"`
+	Extra    map[string]interface{}     `json:"extra"`
+	Groups   map[string][]int           `json:"groups"`
+	Flags    map[string]map[string]bool `json:"flags"`
+	Labels   Labels                     `json:"labels"`
+	Index    Index                      `json:"index"`
+	Settings map[string]struct {
+		On bool `json:"on"`
+	} `json:"settings"`
+}
+
+// @Title Get the catalog.
+// @Route /catalog [get]
+// @Success 200 object Catalog "Catalog."
+// @Success 201 object map[string]User "Users."
+func GetCatalog() {}
@@ -0,0 +1,3 @@
+module example.com/marshalers
+
+go 1.12
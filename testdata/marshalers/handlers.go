@@ -0,0 +1,55 @@
+package main
+
+import "time"
+
+// @Schema {type: string, format: date}
+type Date struct {
+	time.Time
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + d.Format("2006-01-02") + `"`), nil
+}
+
+type Money struct {
+	Units    int64
+	Currency string
+}
+
+func (m *Money) MarshalJSON() ([]byte, error) {
+	return nil, nil
+}
+
+type Status int
+
+const (
+	StatusActive Status = iota
+	StatusClosed
+)
+
+func (s Status) MarshalText() ([]byte, error) {
+	return nil, nil
+}
+
+type Color string
+
+const (
+	ColorRed  Color = "red"
+	ColorBlue Color = "blue"
+)
+
+func (c Color) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+type Order struct {
+	Date   Date   `json:"date"`
+	Total  Money  `json:"total"`
+	Status Status `json:"status"`
+	Color  Color  `json:"color"`
+}
+
+// @Title Get an order.
+// @Route /orders [get]
+// @Success 200 object Order "Order."
+func GetOrder() {}
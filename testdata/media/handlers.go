@@ -0,0 +1,23 @@
+package main
+
+type User struct {
+	Name string `json:"name"`
+}
+
+// @Title Put user.
+// @Accept json, xml
+// @Produce json
+// @Param user body User true "The user."
+// @Success 200 object User "The user."
+// @Success 201 object User xml,text/x-yaml "The created user."
+// @Failure 400 object string plain "The error."
+// @Failure 500 object string The error, unquoted.
+// @Route /user [put]
+func PutUser() {}
+
+// @Title Login.
+// @Accept urlencoded
+// @Param name form string true "The name."
+// @Success 200 object string octet-stream "The token."
+// @Route /login [post]
+func Login() {}
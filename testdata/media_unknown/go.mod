@@ -0,0 +1,3 @@
+module example.com/media_unknown
+
+go 1.12
@@ -0,0 +1,7 @@
+package main
+
+// @Title Get user.
+// @Produce jsno
+// @Success 200 object string "The user."
+// @Route /user [get]
+func GetUser() {}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"database/sql"
+)
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Status string
+
+type Profile struct {
+	Nickname *string           `json:"nickname"`
+	Leader   *User             `json:"leader"`
+	Status   *Status           `json:"status"`
+	Tags     *[]string         `json:"tags"`
+	Bio      sql.NullString    `json:"bio"`
+	Age      sql.NullInt64     `json:"age"`
+	Seen     sql.NullTime      `json:"seen"`
+	Score    sql.Null[float64] `json:"score"`
+	Friend   sql.Null[User]    `json:"friend"`
+	Motto    string            `json:"motto" goas:"nullable"`
+	Name     string            `json:"name"`
+}
+
+// @Title Get a profile.
+// @Route /profiles/{id} [get]
+// @Success 200 object Profile "Profile."
+func GetProfile() {}
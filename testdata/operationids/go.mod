@@ -0,0 +1,3 @@
+module example.com/operationids
+
+go 1.12
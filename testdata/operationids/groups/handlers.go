@@ -0,0 +1,6 @@
+package groups
+
+// @Title List groups.
+// @Success 204
+// @Route /groups [get]
+func List() {}
@@ -0,0 +1,19 @@
+package users
+
+type Handler struct{}
+
+// @Title List users.
+// @Success 204
+// @Route /users [get]
+func List() {}
+
+// @Title Get a user.
+// @Success 204
+// @Route /users/{id} [get]
+func (h *Handler) Get() {}
+
+// @Title Delete a user.
+// @OperationID deleteUser
+// @Success 204
+// @Route /users/{id} [delete]
+func Delete() {}
@@ -0,0 +1,3 @@
+module example.com/operationids_duplicate
+
+go 1.12
@@ -0,0 +1,13 @@
+package main
+
+// @Title List users.
+// @OperationID list
+// @Success 204
+// @Route /users [get]
+func ListUsers() {}
+
+// @Title List groups.
+// @OperationID list
+// @Success 204
+// @Route /groups [get]
+func ListGroups() {}
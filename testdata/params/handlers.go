@@ -0,0 +1,22 @@
+package main
+
+import (
+	"time"
+
+	_ "example.com/params/models"
+)
+
+// @Title List users.
+// @Param ids query []int false "Ids of users." style=form explode=false
+// @Param sort query string false "Sort order." enum=asc,desc
+// @Param since query time.Time false "Since."
+// @Param status query models.Status false "Status."
+// @Param statuses query []models.Status false "Statuses." style=pipeDelimited
+// @Param lvl query models.Level false "Level." enum=1,2
+// @Param lvls query []models.Level false "Levels." enum=2
+// @Param q query string false "Query." allowReserved=true
+// @Param X-Request-ID header string true "Request id."
+// @Param id path int64 false "Id."
+// @Route /users/{id} [get]
+// @Success 204
+func ListUsers() {}
@@ -0,0 +1,16 @@
+package models
+
+type Level int
+
+const (
+	LevelLow Level = iota
+	LevelMid
+	LevelHigh
+)
+
+type Status string
+
+const (
+	StatusActive Status = "active"
+	StatusBanned Status = "banned"
+)
@@ -0,0 +1,3 @@
+module example.com/paramstruct
+
+go 1.12
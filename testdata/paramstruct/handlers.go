@@ -0,0 +1,23 @@
+package main
+
+type Filter struct {
+	ID      int64  `path:"id"`
+	Page    int    `query:"page" example:"1"`
+	Sort    string `form:"sort" required:""`
+	Token   string `header:"X-Token" description:"The token."`
+	Session string `cookie:"session"`
+	Search  string `json:"q"`
+	Skipped string `query:"-"`
+}
+
+// @Title List users.
+// @Params Filter
+// @Success 204
+// @Route /users/{id} [get]
+func ListUsers() {}
+
+// @Title List groups.
+// @Param . query Filter
+// @Success 204
+// @Route /groups/{id} [get]
+func ListGroups() {}
@@ -0,0 +1,3 @@
+module example.com/polymorphism
+
+go 1.12
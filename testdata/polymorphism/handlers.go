@@ -0,0 +1,53 @@
+package main
+
+// @OneOf Cat Dog Bird
+// @Discriminator kind
+type Animal interface {
+	Sound() string
+}
+
+type Cat struct {
+	Kind string `json:"kind" const:"cat"`
+}
+
+type Dog struct {
+	Kind string `json:"kind" enum:"dog"`
+}
+
+type Bird struct {
+	Kind string `json:"kind"`
+}
+
+// @AnyOf
+type Shape interface {
+	Area() float64
+}
+
+type Circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c Circle) Area() float64 { return 0 }
+
+type Square struct {
+	Side float64 `json:"side"`
+}
+
+func (s *Square) Area() float64 { return 0 }
+
+type Line struct {
+	Length float64 `json:"length"`
+}
+
+type Zoo struct {
+	Star    Animal        `json:"star"`
+	Animals []Animal      `json:"animals"`
+	Shapes  []Shape       `json:"shapes"`
+	Pets    []interface{} `json:"pets" goas:"oneOf=Cat|*Bird,discriminator=kind"`
+	Any     interface{}   `json:"any" goas:"anyOf=Cat|Dog"`
+}
+
+// @Title Get the zoo.
+// @Route /zoo [get]
+// @Success 200 object Zoo "Zoo."
+func GetZoo() {}
@@ -0,0 +1,5 @@
+package main
+
+// @Version 1.0.0
+// @Title Polymorphism API
+func main() {}
@@ -0,0 +1,3 @@
+module example.com/polymorphism_error
+
+go 1.12
@@ -0,0 +1,13 @@
+package main
+
+// @OneOf
+type Animal interface{}
+
+type Zoo struct {
+	Star Animal `json:"star"`
+}
+
+// @Title Get the zoo.
+// @Route /zoo [get]
+// @Success 200 object Zoo "Zoo."
+func GetZoo() {}
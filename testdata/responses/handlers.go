@@ -0,0 +1,18 @@
+package main
+
+type Error struct {
+	Message string `json:"message"`
+}
+
+// @Title Delete user.
+// @Success 204
+// @Failure 404 "No such user."
+// @Failure default object Error
+// @Route /users/{id} [delete]
+func DeleteUser() {}
+
+// @Title Ping.
+// @Success 299
+// @Failure default
+// @Route /ping [get]
+func Ping() {}
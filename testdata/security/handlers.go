@@ -0,0 +1,25 @@
+package main
+
+// @Title Inherited.
+// @Success 204
+// @Route /inherited [get]
+func Inherited() {}
+
+// @Title Scoped.
+// @Security OAuth read write
+// @Success 204
+// @Route /scoped [get]
+func Scoped() {}
+
+// @Title Either or both.
+// @Security ApiKey
+// @Security Bearer && OAuth read
+// @Success 204
+// @Route /either [get]
+func Either() {}
+
+// @Title Public.
+// @NoSecurity
+// @Success 204
+// @Route /public [get]
+func Public() {}
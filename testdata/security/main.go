@@ -0,0 +1,11 @@
+package main
+
+// @Version 1.0.0
+// @Title Security API
+// @SecurityScheme Bearer http bearer Input your token
+// @SecurityScheme ApiKey apiKey header X-Api-Key
+// @SecurityScheme OAuth oauth2AuthCode /oauth/authorize /oauth/token
+// @SecurityScope OAuth read Read access
+// @SecurityScope OAuth write Write access
+// @Security Bearer
+func main() {}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"time"
+
+	"example.com/typemapping/id"
+	m "example.com/typemapping/money"
+)
+
+type Order struct {
+	ID      id.ULID             `json:"id"`
+	Total   m.Amount            `json:"total"`
+	Items   []m.Amount          `json:"items"`
+	Taxes   map[string]m.Amount `json:"taxes"`
+	Timeout time.Duration       `json:"timeout"`
+}
+
+// @Title Get an order.
+// @Param id path id.ULID true "Id."
+// @Param min query m.Amount false "Minimum."
+// @Route /orders/{id} [get]
+// @Success 200 object Order "Order."
+// @Success 201 object m.Amount "Total."
+func GetOrder() {}
@@ -0,0 +1,4 @@
+package id
+
+// ULID is marshaled as a string.
+type ULID [16]byte
@@ -0,0 +1,7 @@
+package money
+
+// Amount is marshaled as a decimal string.
+type Amount struct {
+	units int64
+	nanos int32
+}
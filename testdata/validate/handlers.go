@@ -0,0 +1,20 @@
+package main
+
+// @Title Create a user.
+// @Route /users [post]
+// @Param user body User true "User."
+// @Success 204
+func CreateUser() {}
+
+type User struct {
+	Name     string   `json:"name" validate:"required,min=1,max=64"`
+	Email    string   `json:"email" validate:"omitempty,email"`
+	Birthday string   `json:"birthday" validate:"datetime=2006-01-02"`
+	Alarm    string   `json:"alarm" validate:"datetime=15:04"`
+	Age      int      `json:"age" validate:"gte=0,lt=150"`
+	Role     Role     `json:"role" validate:"oneof=admin member"`
+	Tags     []string `json:"tags" validate:"unique,max=3,dive,alphanum,max=16"`
+	Contact  string   `json:"contact" validate:"email|uuid"`
+}
+
+type Role string
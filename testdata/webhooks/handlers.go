@@ -0,0 +1,12 @@
+package main
+
+type User struct {
+	Name  string  `json:"name"`
+	Email *string `json:"email" example:"bob@example.com"`
+}
+
+// @Title User created.
+// @Param user body User true "The created user."
+// @Success 204 "Received."
+// @Webhook userCreated [post]
+func UserCreated() {}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	dbsql "database/sql"
+	"encoding/json"
+	"math/big"
+	"net"
+	neturl "net/url"
+	"time"
+
+	gouuid "github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type Link neturl.URL
+
+type Event struct {
+	ID      gouuid.UUID                `json:"id"`
+	At      time.Time                  `json:"at"`
+	Took    time.Duration              `json:"took"`
+	Payload json.RawMessage            `json:"payload"`
+	Count   json.Number                `json:"count"`
+	Source  neturl.URL                 `json:"source"`
+	Link    Link                       `json:"link"`
+	Addr    net.IP                     `json:"addr"`
+	Price   decimal.Decimal            `json:"price"`
+	Big     *big.Int                   `json:"big"`
+	Data    []byte                     `json:"data"`
+	Note    dbsql.NullString           `json:"note"`
+	Times   []time.Time                `json:"times"`
+	Prices  map[string]decimal.Decimal `json:"prices"`
+}
+
+// @Title Get an event.
+// @Param id path gouuid.UUID true "Id."
+// @Param since query time.Time false "Since."
+// @Route /events/{id} [get]
+// @Success 200 object Event "Event."
+// @Success 201 object neturl.URL "Source."
+// @Success 202 object []gouuid.UUID "Ids."
+// @Header 200 X-Took time.Duration "Took."
+func GetEvent() {}
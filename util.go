@@ -2,9 +2,18 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"io/ioutil"
 	"log"
 	"os"
+	"regexp"
 	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
 )
 
 func isMainFile(path string) bool {
@@ -136,6 +145,296 @@ var goTypesOASFormats = map[string]string{
 	"string":  "string",
 }
 
+var goTypeArrayLenRegexp = regexp.MustCompile(`\w*\[[\w.,]*\]`)
+
+// normalizeGoType turns the arrays of a type in comment, like [5]int, into slices,
+// and keeps the key types of maps, like map[int]string, and the type arguments of generic types.
+func normalizeGoType(goType string) string {
+	return goTypeArrayLenRegexp.ReplaceAllStringFunc(goType, func(s string) string {
+		if !strings.HasPrefix(s, "[") {
+			return s
+		}
+		return "[]"
+	})
+}
+
+// wellKnownTypes are the schemas of the types of the standard library and of common libraries,
+// which are used instead of the schemas of their declarations. Add an entry to support another type.
+var wellKnownTypes = map[string]SchemaObject{
+	"time.Time":       {Type: "string", Format: "date-time"},
+	"time.Duration":   {Type: "integer", Format: "int64"}, // nanoseconds
+	"[]byte":          {Type: "string", Format: "byte"},   // base64 encoded
+	"[]uint8":         {Type: "string", Format: "byte"},
+	"json.RawMessage": {},
+	"json.Number":     {Type: "number"},
+	"uuid.UUID":       {Type: "string", Format: "uuid"},
+	"url.URL":         {Type: "object"}, // the fields of the struct, since it is not a json.Marshaler
+	"net.IP":          {Type: "string"}, // IPv4 or IPv6
+	"decimal.Decimal": {Type: "string", Format: "decimal"},
+	"big.Int":         {Type: "integer"},
+}
+
+// wellKnownPkgs are the import paths of the packages of the well-known types, to their names in wellKnownTypes.
+var wellKnownPkgs = map[string]string{
+	"time":                          "time",
+	"encoding/json":                 "json",
+	"github.com/google/uuid":        "uuid",
+	"github.com/gofrs/uuid":         "uuid",
+	"github.com/satori/go.uuid":     "uuid",
+	"net/url":                       "url",
+	"net":                           "net",
+	"github.com/shopspring/decimal": "decimal",
+	"math/big":                      "big",
+	"database/sql":                  "sql",
+}
+
+// getWellKnownSchemaObject returns a copy of the schema of a well-known type.
+func getWellKnownSchemaObject(typeName string) (*SchemaObject, bool) {
+	schema, ok := wellKnownTypes[typeName]
+	if !ok {
+		return nil, false
+	}
+	return &schema, true
+}
+
+// isWellKnownType reports whether the type has a schema of its own, which is
+// a well-known type or a nullable type of database/sql.
+func isWellKnownType(typeName string) bool {
+	_, ok := wellKnownTypes[typeName]
+	return ok || isSQLNullType(typeName)
+}
+
+// sqlNullTypes are the nullable types of database/sql, to the types of their values.
+var sqlNullTypes = map[string]string{
+	"sql.NullBool":    "bool",
+	"sql.NullByte":    "uint8",
+	"sql.NullFloat64": "float64",
+	"sql.NullInt16":   "int16",
+	"sql.NullInt32":   "int32",
+	"sql.NullInt64":   "int64",
+	"sql.NullString":  "string",
+	"sql.NullTime":    "time.Time",
+}
+
+// getSQLNullValueType returns the type of the value of a nullable type of database/sql,
+// like string of sql.NullString, or User of sql.Null[User].
+func getSQLNullValueType(typeName string) (string, bool) {
+	if valueTypeName, ok := sqlNullTypes[typeName]; ok {
+		return valueTypeName, true
+	}
+	if genericTypeName, typeArgs, ok := parseGenericType(typeName); ok && genericTypeName == "sql.Null" && len(typeArgs) == 1 {
+		return typeArgs[0], true
+	}
+	return "", false
+}
+
+func isSQLNullType(typeName string) bool {
+	_, ok := getSQLNullValueType(typeName)
+	return ok
+}
+
+// setNullable marks the schema as nullable.
+func setNullable(schema *SchemaObject) {
+	wrapRef(schema)
+	schema.Nullable = true
+}
+
+// wrapRef moves the reference of the schema into allOf, since the siblings of $ref,
+// like nullable, enum or constraints, are ignored.
+func wrapRef(schema *SchemaObject) {
+	if schema.Ref != "" {
+		schema.AllOf = []*SchemaObject{{Ref: schema.Ref}}
+		schema.Ref = ""
+	}
+}
+
+// parseGenericType splits an instantiated generic type, like Page[User] or models.Pair[int,string],
+// into the name of the generic type and the type arguments.
+func parseGenericType(typeName string) (string, []string, bool) {
+	i := strings.Index(typeName, "[")
+	if i <= 0 || !strings.HasSuffix(typeName, "]") || strings.HasPrefix(typeName, "map[") {
+		return "", nil, false
+	}
+	typeArgs := []string{}
+	depth, start := 0, i+1
+	for j := start; j < len(typeName)-1; j++ {
+		switch typeName[j] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				typeArgs = append(typeArgs, strings.TrimSpace(typeName[start:j]))
+				start = j + 1
+			}
+		}
+	}
+	typeArgs = append(typeArgs, strings.TrimSpace(typeName[start:len(typeName)-1]))
+	return typeName[:i], typeArgs, true
+}
+
+// parseTypeOverrides splits a type with property overrides, like Response{data=[]User,meta=PageMeta},
+// into the type name and the pairs of property name and type.
+func parseTypeOverrides(typeName string) (string, [][2]string, bool) {
+	i := strings.Index(typeName, "{")
+	if i <= 0 || !strings.HasSuffix(typeName, "}") {
+		return "", nil, false
+	}
+	overrides := [][2]string{}
+	depth, start := 0, i+1
+	for j := start; j <= len(typeName)-1; j++ {
+		switch typeName[j] {
+		case '[', '{':
+			depth++
+		case ']':
+			depth--
+		case '}':
+			if j != len(typeName)-1 {
+				depth--
+				continue
+			}
+			fallthrough
+		case ',':
+			if depth != 0 {
+				continue
+			}
+			override := strings.SplitN(typeName[start:j], "=", 2)
+			if len(override) == 2 && override[0] != "" && override[1] != "" {
+				overrides = append(overrides, [2]string{strings.TrimSpace(override[0]), strings.TrimSpace(override[1])})
+			}
+			start = j + 1
+		}
+	}
+	return typeName[:i], overrides, true
+}
+
+// getTypeArgName returns the name of a resolved type argument in the component name of an instantiation,
+// like User of example.com.models.User, or Array_User of []example.com.models.User.
+// With qualified, the package of a named type is kept, like example.com.models.User.
+func getTypeArgName(typeArg string, qualified bool) string {
+	if strings.HasPrefix(typeArg, "[]") {
+		return "Array_" + getTypeArgName(typeArg[2:], qualified)
+	} else if _, valueTypeName, ok := parseMapType(typeArg); ok {
+		return "Map_" + getTypeArgName(valueTypeName, qualified)
+	} else if typeArg == "interface{}" {
+		return "Any"
+	} else if !qualified {
+		return typeArg[strings.LastIndex(typeArg, ".")+1:]
+	}
+	return typeArg
+}
+
+// parseMapType splits a map type like map[string][]User into its key and value types.
+func parseMapType(typeName string) (keyTypeName, valueTypeName string, ok bool) {
+	if !strings.HasPrefix(typeName, "map[") {
+		return "", "", false
+	}
+	depth := 0
+	for i := len("map"); i < len(typeName); i++ {
+		switch typeName[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return typeName[len("map["):i], typeName[i+1:], i+1 < len(typeName)
+			}
+		}
+	}
+	return "", "", false
+}
+
+// getFuncRecvTypeName returns the receiver type name of a method, or "" for a func.
+func getFuncRecvTypeName(astFuncDeclaration *ast.FuncDecl) string {
+	if astFuncDeclaration.Recv == nil || len(astFuncDeclaration.Recv.List) == 0 {
+		return ""
+	}
+	recvType := astFuncDeclaration.Recv.List[0].Type
+	if astStarExpr, ok := recvType.(*ast.StarExpr); ok {
+		recvType = astStarExpr.X
+	}
+	// the receiver of a generic type, like Page[T]
+	switch astType := recvType.(type) {
+	case *ast.IndexExpr:
+		recvType = astType.X
+	case *ast.IndexListExpr:
+		recvType = astType.X
+	}
+	if astIdent, ok := recvType.(*ast.Ident); ok {
+		return astIdent.String()
+	}
+	return ""
+}
+
+var operationIDInvalidCharRegexp = regexp.MustCompile(`\W`)
+
+// getOperationIDPrefix returns the name of the package for the generated operationId,
+// like users of example.com/api/users.
+func getOperationIDPrefix(pkgName string) string {
+	pkgName = pkgName[strings.LastIndex(pkgName, "/")+1:]
+	return operationIDInvalidCharRegexp.ReplaceAllString(pkgName, "_")
+}
+
+// getDeprecatedReason finds the paragraph which begins with "Deprecated:" in the doc comments.
+func getDeprecatedReason(astCommentGroup *ast.CommentGroup) (string, bool) {
+	if astCommentGroup == nil {
+		return "", false
+	}
+	for _, paragraph := range strings.Split(astCommentGroup.Text(), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if !strings.HasPrefix(paragraph, "Deprecated:") {
+			continue
+		}
+		// the reason ends before the annotations, like @Title, which follow it
+		lines := []string{}
+		for _, line := range strings.Split(strings.TrimPrefix(paragraph, "Deprecated:"), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "@") {
+				break
+			}
+			lines = append(lines, line)
+		}
+		return strings.Join(strings.Fields(strings.Join(lines, " ")), " "), true
+	}
+	return "", false
+}
+
+var mediaTypeAliases = map[string]string{
+	"json":                  "application/json",
+	"xml":                   "application/xml",
+	"plain":                 "text/plain",
+	"text":                  "text/plain",
+	"html":                  "text/html",
+	"csv":                   "text/csv",
+	"form":                  "multipart/form-data",
+	"mpfd":                  "multipart/form-data",
+	"x-www-form-urlencoded": "application/x-www-form-urlencoded",
+	"urlencoded":            "application/x-www-form-urlencoded",
+	"octet-stream":          "application/octet-stream",
+	"json-api":              "application/vnd.api+json",
+	"json-stream":           "application/x-json-stream",
+	"png":                   "image/png",
+	"jpeg":                  "image/jpeg",
+	"gif":                   "image/gif",
+}
+
+// parseMediaTypes parses media types separated by spaces or commas, like "json, xml" or "text/csv".
+// A word which is neither an alias nor a media type is an error.
+func parseMediaTypes(value string) ([]string, error) {
+	mediaTypes := []string{}
+	for _, v := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		if mediaType, ok := mediaTypeAliases[strings.ToLower(v)]; ok {
+			v = mediaType
+		} else if !strings.Contains(v, "/") {
+			return nil, fmt.Errorf("parseMediaTypes: unknown media type %s", v)
+		}
+		if !isInStringList(mediaTypes, v) {
+			mediaTypes = append(mediaTypes, v)
+		}
+	}
+	return mediaTypes, nil
+}
+
 // var typeDefTranslations = map[string]string{}
 
 // var modelNamesPackageNames = map[string]string{}
@@ -160,3 +459,74 @@ func genSchemeaObjectID(pkgName, typeName string) string {
 func replaceBackslash(origin string) string {
 	return strings.ReplaceAll(origin, "\\", "/")
 }
+
+// parseTypeMappingFile parses the YAML or JSON file which maps fully qualified Go types to schemas.
+//
+//	github.com/acme/core/money.Amount:
+//	  type: string
+//	  format: decimal
+//	github.com/acme/core/id.ULID:
+//	  $ref: 'https://acme.com/schemas.yaml#/ULID'
+func parseTypeMappingFile(path string) (map[string]*SchemaObject, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("parseTypeMappingFile: %s", err)
+	}
+	typeMappings := map[string]*SchemaObject{}
+	err = unmarshalYaml(b, &typeMappings)
+	if err != nil {
+		return nil, fmt.Errorf("parseTypeMappingFile: cannot parse %s: %s", path, err)
+	}
+	for typeName, schema := range typeMappings {
+		if schema == nil {
+			return nil, fmt.Errorf("parseTypeMappingFile: no schema of %s", typeName)
+		}
+	}
+	return typeMappings, nil
+}
+
+// unmarshalYaml unmarshals a YAML document, or a JSON document which is YAML too,
+// into v by the json tags of v.
+func unmarshalYaml(b []byte, v interface{}) error {
+	var value interface{}
+	err := yaml.Unmarshal(b, &value)
+	if err != nil {
+		return err
+	}
+	b, err = json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// jsonToYaml converts the marshaled JSON document into YAML. Decoding into
+// yaml.Node keeps the key order of the JSON output (e.g. orderedmap properties).
+func jsonToYaml(b []byte) ([]byte, error) {
+	var node yaml.Node
+	err := yaml.Unmarshal(b, &node)
+	if err != nil {
+		return nil, err
+	}
+	resetYamlNodeStyle(&node)
+
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	err = encoder.Encode(&node)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func resetYamlNodeStyle(node *yaml.Node) {
+	node.Style = 0
+	for i := range node.Content {
+		resetYamlNodeStyle(node.Content[i])
+	}
+}
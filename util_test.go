@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetTypeArgName(t *testing.T) {
+	tests := []struct {
+		typeArg   string
+		want      string
+		qualified string
+	}{
+		{"int", "int", "int"},
+		{"example.com.models.User", "User", "example.com.models.User"},
+		{"example.com.core.Page_User", "Page_User", "example.com.core.Page_User"},
+		{"[]example.com.models.User", "Array_User", "Array_example.com.models.User"},
+		{"[][]string", "Array_Array_string", "Array_Array_string"},
+		{"map[string]example.com.models.User", "Map_User", "Map_example.com.models.User"},
+		{"map[string][]int", "Map_Array_int", "Map_Array_int"},
+		{"interface{}", "Any", "Any"},
+		{"[]interface{}", "Array_Any", "Array_Any"},
+		{"time.Time", "Time", "time.Time"},
+	}
+	for _, tt := range tests {
+		if got := getTypeArgName(tt.typeArg, false); got != tt.want {
+			t.Errorf("getTypeArgName(%q, false) = %q, want %q", tt.typeArg, got, tt.want)
+		}
+		if got := getTypeArgName(tt.typeArg, true); got != tt.qualified {
+			t.Errorf("getTypeArgName(%q, true) = %q, want %q", tt.typeArg, got, tt.qualified)
+		}
+	}
+}
+
+func TestJsonToYaml(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{`{}`, "{}\n"},
+		{`{"b":1,"a":"x"}`, "b: 1\na: x\n"},
+		{`{"paths":{"/users":{"get":{"tags":["users"]}}}}`, "paths:\n  /users:\n    get:\n      tags:\n        - users\n"},
+		{`{"description":"a: b","enum":["true","1"]}`, "description: 'a: b'\nenum:\n  - \"true\"\n  - \"1\"\n"},
+	}
+	for _, tt := range tests {
+		got, err := jsonToYaml([]byte(tt.json))
+		if err != nil {
+			t.Errorf("jsonToYaml(%s) error: %s", tt.json, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("jsonToYaml(%s) = %q, want %q", tt.json, got, tt.want)
+		}
+	}
+}
+
+func TestGetOperationIDPrefix(t *testing.T) {
+	tests := []struct {
+		pkgName string
+		want    string
+	}{
+		{"main", "main"},
+		{"example.com/api/users", "users"},
+		{"example.com/api/user-groups", "user_groups"},
+		{"example.com/api/v1.2", "v1_2"},
+	}
+	for _, tt := range tests {
+		if got := getOperationIDPrefix(tt.pkgName); got != tt.want {
+			t.Errorf("getOperationIDPrefix(%q) = %q, want %q", tt.pkgName, got, tt.want)
+		}
+	}
+}
+
+func TestGetDeprecatedReason(t *testing.T) {
+	tests := []struct {
+		comments   []string
+		reason     string
+		deprecated bool
+	}{
+		{nil, "", false},
+		{[]string{"// ListUsers lists the users."}, "", false},
+		{[]string{"// Deprecated: use ListUsers2."}, "use ListUsers2.", true},
+		{[]string{"// ListUsers lists the users.", "//", "// Deprecated: use ListUsers2,", "// which is paged."}, "use ListUsers2, which is paged.", true},
+		{[]string{"// Deprecated: use ListUsers2.", "// @Title List users.", "// @Route /users [get]"}, "use ListUsers2.", true},
+		{[]string{"// Deprecated:"}, "", true},
+		{[]string{"// Not Deprecated: at the beginning."}, "", false},
+		{[]string{"/* Deprecated: in a block. */"}, "in a block.", true},
+	}
+	for _, tt := range tests {
+		var astCommentGroup *ast.CommentGroup
+		if tt.comments != nil {
+			astCommentGroup = &ast.CommentGroup{}
+			for _, comment := range tt.comments {
+				astCommentGroup.List = append(astCommentGroup.List, &ast.Comment{Text: comment})
+			}
+		}
+		reason, deprecated := getDeprecatedReason(astCommentGroup)
+		if reason != tt.reason || deprecated != tt.deprecated {
+			t.Errorf("getDeprecatedReason(%q) = %q, %v, want %q, %v", tt.comments, reason, deprecated, tt.reason, tt.deprecated)
+		}
+	}
+}
+
+func TestParseMediaTypes(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{"", []string{}, false},
+		{"json", []string{"application/json"}, false},
+		{" JSON, xml ", []string{"application/json", "application/xml"}, false},
+		{"json application/json", []string{"application/json"}, false},
+		{"text/csv,octet-stream", []string{"text/csv", "application/octet-stream"}, false},
+		{"application/vnd.acme+json", []string{"application/vnd.acme+json"}, false},
+		{"jsno", nil, true},
+		{"json The user.", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseMediaTypes(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMediaTypes(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseMediaTypes(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseMapType(t *testing.T) {
+	tests := []struct {
+		typeName  string
+		keyType   string
+		valueType string
+		ok        bool
+	}{
+		{"map[string]User", "string", "User", true},
+		{"map[int64][]models.User", "int64", "[]models.User", true},
+		{"map[string]map[int]bool", "string", "map[int]bool", true},
+		{"map[string][]map[string]interface{}", "string", "[]map[string]interface{}", true},
+		{"map[string]Page[User]", "string", "Page[User]", true},
+		{"map[Key[int]]string", "Key[int]", "string", true},
+		{"map[string]", "", "", false},
+		{"map[string", "", "", false},
+		{"[]map[string]int", "", "", false},
+		{"User", "", "", false},
+	}
+	for _, tt := range tests {
+		keyType, valueType, ok := parseMapType(tt.typeName)
+		if ok != tt.ok || (ok && (keyType != tt.keyType || valueType != tt.valueType)) {
+			t.Errorf("parseMapType(%s) = %s, %s, %v, want %s, %s, %v", tt.typeName, keyType, valueType, ok, tt.keyType, tt.valueType, tt.ok)
+		}
+	}
+}
+
+func TestParseGenericType(t *testing.T) {
+	tests := []struct {
+		typeName string
+		name     string
+		typeArgs []string
+		ok       bool
+	}{
+		{"Page[User]", "Page", []string{"User"}, true},
+		{"models.Pair[int, string]", "models.Pair", []string{"int", "string"}, true},
+		{"Page[[]models.User]", "Page", []string{"[]models.User"}, true},
+		{"Page[map[string]User]", "Page", []string{"map[string]User"}, true},
+		{"Page[Pair[int,string]]", "Page", []string{"Pair[int,string]"}, true},
+		{"Pair[Page[User],map[int]User]", "Pair", []string{"Page[User]", "map[int]User"}, true},
+		{"User", "", nil, false},
+		{"[]User", "", nil, false},
+		{"map[string]User", "", nil, false},
+		{"Page[User]{data=User}", "", nil, false},
+	}
+	for _, tt := range tests {
+		name, typeArgs, ok := parseGenericType(tt.typeName)
+		if ok != tt.ok || name != tt.name || !reflect.DeepEqual(typeArgs, tt.typeArgs) {
+			t.Errorf("parseGenericType(%s) = %s, %q, %v, want %s, %q, %v", tt.typeName, name, typeArgs, ok, tt.name, tt.typeArgs, tt.ok)
+		}
+	}
+}
+
+func TestParseTypeOverrides(t *testing.T) {
+	tests := []struct {
+		typeName  string
+		name      string
+		overrides [][2]string
+		ok        bool
+	}{
+		{"Response{data=User}", "Response", [][2]string{{"data", "User"}}, true},
+		{"Response{data=[]User,meta=PageMeta}", "Response", [][2]string{{"data", "[]User"}, {"meta", "PageMeta"}}, true},
+		{"Response{ data = User , meta=PageMeta }", "Response", [][2]string{{"data", "User"}, {"meta", "PageMeta"}}, true},
+		{"models.Response{data=map[string]User}", "models.Response", [][2]string{{"data", "map[string]User"}}, true},
+		{"Response{data=Page[User]}", "Response", [][2]string{{"data", "Page[User]"}}, true},
+		{"Response{data=Page{items=[]User},meta=PageMeta}", "Response", [][2]string{{"data", "Page{items=[]User}"}, {"meta", "PageMeta"}}, true},
+		{"Response{data=Pair[int,string]}", "Response", [][2]string{{"data", "Pair[int,string]"}}, true},
+		{"Response{data=,=User,meta}", "Response", [][2]string{}, true},
+		{"Response{}", "Response", [][2]string{}, true},
+		{"Response", "", nil, false},
+		{"Response{data=User", "", nil, false},
+		{"{data=User}", "", nil, false},
+	}
+	for _, tt := range tests {
+		name, overrides, ok := parseTypeOverrides(tt.typeName)
+		if ok != tt.ok || name != tt.name || !reflect.DeepEqual(overrides, tt.overrides) {
+			t.Errorf("parseTypeOverrides(%s) = %s, %q, %v, want %s, %q, %v", tt.typeName, name, overrides, ok, tt.name, tt.overrides, tt.ok)
+		}
+	}
+}
+
+func TestNormalizeGoType(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"int", "int"},
+		{"[]int", "[]int"},
+		{"[5]int", "[]int"},
+		{"[N]models.User", "[]models.User"},
+		{"[2][3]int", "[][]int"},
+		{"map[int]string", "map[int]string"},
+		{"map[string][3]int", "map[string][]int"},
+		{"Page[User]", "Page[User]"},
+		{"[]Pair[int,string]", "[]Pair[int,string]"},
+		{"Response{data=[4]User}", "Response{data=[]User}"},
+	}
+	for _, tt := range tests {
+		if got := normalizeGoType(tt.goType); got != tt.want {
+			t.Errorf("normalizeGoType(%s) = %s, want %s", tt.goType, got, tt.want)
+		}
+	}
+}
+
+func TestGetSQLNullValueType(t *testing.T) {
+	tests := []struct {
+		typeName string
+		want     string
+		ok       bool
+	}{
+		{"sql.NullString", "string", true},
+		{"sql.NullInt64", "int64", true},
+		{"sql.NullTime", "time.Time", true},
+		{"sql.Null[float64]", "float64", true},
+		{"sql.Null[models.User]", "models.User", true},
+		{"sql.Null[int,string]", "", false},
+		{"sql.DB", "", false},
+		{"NullString", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := getSQLNullValueType(tt.typeName)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("getSQLNullValueType(%s) = %s, %v, want %s, %v", tt.typeName, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSetNullable(t *testing.T) {
+	tests := []struct {
+		schema SchemaObject
+		want   SchemaObject
+	}{
+		{SchemaObject{Type: "string"}, SchemaObject{Type: "string", Nullable: true}},
+		{SchemaObject{Type: "object", Ref: "#/components/schemas/User"}, SchemaObject{Type: "object", Nullable: true, AllOf: []*SchemaObject{{Ref: "#/components/schemas/User"}}}},
+	}
+	for _, tt := range tests {
+		schema := tt.schema
+		setNullable(&schema)
+		if !reflect.DeepEqual(schema, tt.want) {
+			t.Errorf("setNullable(%+v) = %+v, want %+v", tt.schema, schema, tt.want)
+		}
+	}
+}
+
+func TestParseTypeMappingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		content string
+		want    string
+		err     string
+	}{
+		{`{"example.com/money.Amount": {"type": "string", "format": "decimal"}}`, `{"example.com/money.Amount": {"type": "string", "format": "decimal"}}`, ""},
+		{"example.com/id.ULID:\n  $ref: 'https://example.com/schemas.yaml#/ULID'\n", `{"example.com/id.ULID": {"$ref": "https://example.com/schemas.yaml#/ULID"}}`, ""},
+		{"example.com/id.ULID:\n", "", "no schema of example.com/id.ULID"},
+		{"- example.com/id.ULID\n", "", "cannot parse"},
+	}
+	for i, tt := range tests {
+		path := filepath.Join(dir, fmt.Sprintf("types%d.yaml", i))
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := parseTypeMappingFile(path)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseTypeMappingFile(%q) error = %v, want %s", tt.content, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseTypeMappingFile(%q) error = %s", tt.content, err)
+			continue
+		}
+		assertJSON(t, tt.content, got, tt.want)
+	}
+	if _, err := parseTypeMappingFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("parseTypeMappingFile of a missing file returned no error")
+	}
+}
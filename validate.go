@@ -0,0 +1,210 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var validateOneOfRegexp = regexp.MustCompile(`'[^']*'|\S+`)
+
+var validateFormats = map[string]string{
+	"email":    "email",
+	"uuid":     "uuid",
+	"uuid3":    "uuid",
+	"uuid4":    "uuid",
+	"uuid5":    "uuid",
+	"uri":      "uri",
+	"url":      "uri",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+	"hostname": "hostname",
+}
+
+// validateDatetimeFormats are the formats of the layouts of datetime, other layouts have no format
+var validateDatetimeFormats = map[string]string{
+	time.RFC3339:           "date-time",
+	time.RFC3339Nano:       "date-time",
+	"2006-01-02T15:04:05Z": "date-time",
+	"2006-01-02":           "date",
+	"15:04:05":             "time",
+}
+
+var validatePatterns = map[string]string{
+	"alpha":       "^[a-zA-Z]+$",
+	"alphanum":    "^[a-zA-Z0-9]+$",
+	"numeric":     "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+	"number":      "^[0-9]+$",
+	"hexadecimal": "^(0[xX])?[0-9a-fA-F]+$",
+	"lowercase":   "^[^A-Z]*$",
+	"uppercase":   "^[^a-z]*$",
+}
+
+// parseValidateTag applies the rules of a go-playground/validator tag, like `validate:"required,min=1,max=100"`,
+// to the schema of the field. It reports whether the field is required, and whether the schema is changed.
+func (p *parser) parseValidateTag(fieldSchema *SchemaObject, tag string) (bool, bool) {
+	var required, changed bool
+	schema := fieldSchema
+	for _, rule := range strings.Split(tag, ",") {
+		kv := strings.SplitN(rule, "=", 2)
+		key, value := kv[0], ""
+		if len(kv) == 2 {
+			value = kv[1]
+		}
+		// alternatives can not be expressed with constraints
+		if strings.Contains(rule, "|") {
+			continue
+		}
+		switch key {
+		case "required":
+			if schema == fieldSchema {
+				required = true
+			}
+			continue
+		case "dive":
+			// the following rules are applied to the items
+			if schema.Type != "array" || schema.Items == nil {
+				return required, changed
+			}
+			itemsSchema := *schema.Items
+			schema.Items = &itemsSchema
+			schema = schema.Items
+			continue
+		case "unique":
+			if schema.Type == "array" {
+				schema.UniqueItems = true
+				changed = true
+			}
+			continue
+		case "oneof":
+			enums := []interface{}{}
+			// values are separated by spaces, or quoted by single quotes
+			for _, v := range validateOneOfRegexp.FindAllString(value, -1) {
+				enum, err := parseValueByType(schema.Type, strings.Trim(v, "'"))
+				if err != nil {
+					p.debugf("parseValidateTag cannot parse oneof value %s: %s", v, err)
+					continue
+				}
+				enums = append(enums, enum)
+			}
+			schema.Enum = enums
+			schema.EnumVarNames = nil
+			changed = true
+			continue
+		case "datetime":
+			if format, ok := validateDatetimeFormats[value]; ok {
+				schema.Format = format
+				changed = true
+			} else {
+				p.debugf("parseValidateTag ignores datetime layout %s which is not a format", value)
+			}
+			continue
+		}
+		if format, ok := validateFormats[key]; ok {
+			schema.Format = format
+			changed = true
+			continue
+		}
+		if pattern, ok := validatePatterns[key]; ok {
+			schema.Pattern = pattern
+			changed = true
+			continue
+		}
+		if value == "" {
+			continue
+		}
+		if setValidateConstraint(schema, key, value) {
+			changed = true
+		}
+	}
+	return required, changed
+}
+
+// setValidateConstraint sets the constraint of min, max, len, eq, gt, gte, lt and lte,
+// which is a value of a number, or a length of a string, an array or an object.
+// eq is the value itself of a number, a string or a bool.
+func setValidateConstraint(schema *SchemaObject, key, value string) bool {
+	if key == "eq" && schema.Type != "array" && schema.Type != "object" {
+		enum, err := parseValueByType(schema.Type, value)
+		if err != nil {
+			return false
+		}
+		schema.Enum = []interface{}{enum}
+		schema.EnumVarNames = nil
+		return true
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false
+		}
+		switch key {
+		case "min", "gte":
+			schema.Minimum = &n
+		case "max", "lte":
+			schema.Maximum = &n
+		case "len":
+			schema.Minimum, schema.Maximum = &n, &n
+		case "gt":
+			schema.Minimum, schema.ExclusiveMinimum = &n, true
+		case "lt":
+			schema.Maximum, schema.ExclusiveMaximum = &n, true
+		default:
+			return false
+		}
+		return true
+	case "string", "array", "object":
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		var min, max *uint64
+		switch key {
+		case "min", "gte":
+			min = &n
+		case "max", "lte":
+			max = &n
+		case "len", "eq":
+			min, max = &n, &n
+		case "gt":
+			n++
+			min = &n
+		case "lt":
+			if n == 0 {
+				return false
+			}
+			n--
+			max = &n
+		default:
+			return false
+		}
+		switch schema.Type {
+		case "string":
+			if min != nil {
+				schema.MinLength = min
+			}
+			if max != nil {
+				schema.MaxLength = max
+			}
+		case "array":
+			if min != nil {
+				schema.MinItems = min
+			}
+			if max != nil {
+				schema.MaxItems = max
+			}
+		case "object":
+			if min != nil {
+				schema.MinProperties = min
+			}
+			if max != nil {
+				schema.MaxProperties = max
+			}
+		}
+		return true
+	}
+	return false
+}
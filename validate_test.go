@@ -0,0 +1,95 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetValidateConstraint(t *testing.T) {
+	float64Ptr := func(n float64) *float64 { return &n }
+	uint64Ptr := func(n uint64) *uint64 { return &n }
+	tests := []struct {
+		schema SchemaObject
+		key    string
+		value  string
+		ok     bool
+		want   SchemaObject
+	}{
+		{SchemaObject{Type: "integer"}, "min", "1", true, SchemaObject{Type: "integer", Minimum: float64Ptr(1)}},
+		{SchemaObject{Type: "integer"}, "lte", "9", true, SchemaObject{Type: "integer", Maximum: float64Ptr(9)}},
+		{SchemaObject{Type: "number"}, "gt", "0.5", true, SchemaObject{Type: "number", Minimum: float64Ptr(0.5), ExclusiveMinimum: true}},
+		{SchemaObject{Type: "number"}, "lt", "2", true, SchemaObject{Type: "number", Maximum: float64Ptr(2), ExclusiveMaximum: true}},
+		{SchemaObject{Type: "integer"}, "len", "3", true, SchemaObject{Type: "integer", Minimum: float64Ptr(3), Maximum: float64Ptr(3)}},
+		{SchemaObject{Type: "integer"}, "eq", "3", true, SchemaObject{Type: "integer", Enum: []interface{}{3}}},
+		{SchemaObject{Type: "integer"}, "min", "one", false, SchemaObject{Type: "integer"}},
+		{SchemaObject{Type: "string"}, "min", "1", true, SchemaObject{Type: "string", MinLength: uint64Ptr(1)}},
+		{SchemaObject{Type: "string"}, "gt", "1", true, SchemaObject{Type: "string", MinLength: uint64Ptr(2)}},
+		{SchemaObject{Type: "string"}, "lt", "8", true, SchemaObject{Type: "string", MaxLength: uint64Ptr(7)}},
+		{SchemaObject{Type: "string"}, "lt", "0", false, SchemaObject{Type: "string"}},
+		{SchemaObject{Type: "string"}, "len", "4", true, SchemaObject{Type: "string", MinLength: uint64Ptr(4), MaxLength: uint64Ptr(4)}},
+		{SchemaObject{Type: "string", Enum: []interface{}{"a", "b"}, EnumVarNames: []string{"A", "B"}}, "eq", "a", true, SchemaObject{Type: "string", Enum: []interface{}{"a"}}},
+		{SchemaObject{Type: "array"}, "max", "5", true, SchemaObject{Type: "array", MaxItems: uint64Ptr(5)}},
+		{SchemaObject{Type: "array"}, "eq", "2", true, SchemaObject{Type: "array", MinItems: uint64Ptr(2), MaxItems: uint64Ptr(2)}},
+		{SchemaObject{Type: "object"}, "min", "1", true, SchemaObject{Type: "object", MinProperties: uint64Ptr(1)}},
+		{SchemaObject{Type: "boolean"}, "min", "1", false, SchemaObject{Type: "boolean"}},
+		{SchemaObject{Type: "string"}, "oneof", "a b", false, SchemaObject{Type: "string"}},
+	}
+	for _, tt := range tests {
+		schema := tt.schema
+		ok := setValidateConstraint(&schema, tt.key, tt.value)
+		if ok != tt.ok {
+			t.Errorf("setValidateConstraint(%s, %s=%s) = %v, want %v", tt.schema.Type, tt.key, tt.value, ok, tt.ok)
+		}
+		if !reflect.DeepEqual(schema, tt.want) {
+			t.Errorf("setValidateConstraint(%s, %s=%s) set %+v, want %+v", tt.schema.Type, tt.key, tt.value, schema, tt.want)
+		}
+	}
+}
+
+func TestParseValidateTag(t *testing.T) {
+	uint64Ptr := func(n uint64) *uint64 { return &n }
+	items := func(schema SchemaObject) *SchemaObject { return &schema }
+	tests := []struct {
+		schema   SchemaObject
+		tag      string
+		required bool
+		changed  bool
+		want     SchemaObject
+	}{
+		{SchemaObject{Type: "string"}, "required", true, false, SchemaObject{Type: "string"}},
+		{SchemaObject{Type: "string"}, "required,email", true, true, SchemaObject{Type: "string", Format: "email"}},
+		{SchemaObject{Type: "string"}, "email|uuid", false, false, SchemaObject{Type: "string"}},
+		{SchemaObject{Type: "string"}, "alpha", false, true, SchemaObject{Type: "string", Pattern: "^[a-zA-Z]+$"}},
+		{SchemaObject{Type: "string"}, "datetime=2006-01-02T15:04:05Z07:00", false, true, SchemaObject{Type: "string", Format: "date-time"}},
+		{SchemaObject{Type: "string"}, "datetime=2006-01-02", false, true, SchemaObject{Type: "string", Format: "date"}},
+		{SchemaObject{Type: "string"}, "datetime=15:04:05", false, true, SchemaObject{Type: "string", Format: "time"}},
+		{SchemaObject{Type: "string"}, "datetime=01/02/2006", false, false, SchemaObject{Type: "string"}},
+		{SchemaObject{Type: "string"}, "oneof=red green", false, true, SchemaObject{Type: "string", Enum: []interface{}{"red", "green"}}},
+		{SchemaObject{Type: "string"}, "oneof='light red' green", false, true, SchemaObject{Type: "string", Enum: []interface{}{"light red", "green"}}},
+		{SchemaObject{Type: "integer", Enum: []interface{}{1, 2, 3}, EnumVarNames: []string{"A", "B", "C"}}, "oneof=1 x 3", false, true, SchemaObject{Type: "integer", Enum: []interface{}{1, 3}}},
+		{SchemaObject{Type: "array", Items: &SchemaObject{Type: "string"}}, "unique,min=1", false, true, SchemaObject{Type: "array", Items: &SchemaObject{Type: "string"}, UniqueItems: true, MinItems: uint64Ptr(1)}},
+		{SchemaObject{Type: "array", Items: &SchemaObject{Type: "string"}}, "required,max=3,dive,required,max=8", true, true, SchemaObject{Type: "array", Items: items(SchemaObject{Type: "string", MaxLength: uint64Ptr(8)}), MaxItems: uint64Ptr(3)}},
+		{SchemaObject{Type: "array", Items: &SchemaObject{Type: "array", Items: &SchemaObject{Type: "integer"}}}, "dive,dive,oneof=1 2", false, true, SchemaObject{Type: "array", Items: items(SchemaObject{Type: "array", Items: items(SchemaObject{Type: "integer", Enum: []interface{}{1, 2}})})}},
+		{SchemaObject{Type: "string"}, "dive,email", false, false, SchemaObject{Type: "string"}},
+	}
+	for _, tt := range tests {
+		schema := tt.schema
+		itemsSchema := schema.Items
+		var itemsCopy SchemaObject
+		if itemsSchema != nil {
+			itemsCopy = *itemsSchema
+		}
+		p := &parser{}
+		required, changed := p.parseValidateTag(&schema, tt.tag)
+		if required != tt.required || changed != tt.changed {
+			t.Errorf("parseValidateTag(%s, %s) = %v, %v, want %v, %v", tt.schema.Type, tt.tag, required, changed, tt.required, tt.changed)
+		}
+		if !reflect.DeepEqual(schema, tt.want) {
+			t.Errorf("parseValidateTag(%s, %s) set %+v, want %+v", tt.schema.Type, tt.tag, schema, tt.want)
+		}
+		// the items may be shared with the schema of a named type
+		if itemsSchema != nil && !reflect.DeepEqual(*itemsSchema, itemsCopy) {
+			t.Errorf("parseValidateTag(%s, %s) changed the items %+v", tt.schema.Type, tt.tag, *itemsSchema)
+		}
+	}
+}